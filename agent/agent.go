@@ -0,0 +1,263 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/rpc"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/memoio/memo-client/lib/address"
+	"github.com/memoio/memo-client/wallet"
+	"golang.org/x/xerrors"
+)
+
+const serviceName = "Agent"
+
+var ErrLocked = xerrors.New("agent is locked")
+
+type SignArgs struct {
+	Addr []byte
+	Msg  []byte
+}
+
+// Creds is a gateway access key pair.
+type Creds struct {
+	AccessKey string
+	SecretKey []byte
+}
+
+// Agent holds an unlocked wallet and answers sign requests on a unix socket
+// until its ttl expires.
+type Agent struct {
+	lk     sync.Mutex
+	w      *wallet.LocalWallet
+	expire time.Time
+}
+
+// New unlocks the wallet; it no longer needs the password afterwards.
+func New(ctx context.Context, w *wallet.LocalWallet, ttl time.Duration) (*Agent, error) {
+	err := w.Unlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Agent{
+		w:      w,
+		expire: time.Now().Add(ttl),
+	}, nil
+}
+
+// DefaultSockPath is the socket in the user's runtime dir, or in a
+// private dir under the temp dir when there is none.
+func DefaultSockPath() (string, error) {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "memo-agent.sock"), nil
+	}
+
+	dir := filepath.Join(os.TempDir(), fmt.Sprintf("memo-agent-%d", os.Getuid()))
+	err := os.Mkdir(dir, 0700)
+	if err != nil && !os.IsExist(err) {
+		return "", err
+	}
+
+	fi, err := os.Lstat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !fi.IsDir() || fi.Mode().Perm() != 0700 {
+		return "", xerrors.Errorf("%s is not a private dir", dir)
+	}
+
+	return filepath.Join(dir, "agent.sock"), nil
+}
+
+// listen binds sockPath so that only the user can ever connect: the
+// socket is made in a fresh 0700 dir, restricted, then moved in place.
+func listen(sockPath string) (net.Listener, error) {
+	tmp, err := os.MkdirTemp(filepath.Dir(sockPath), ".memo-agent-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	tmpSock := filepath.Join(tmp, "agent.sock")
+	ln, err := net.Listen("unix", tmpSock)
+	if err != nil {
+		return nil, err
+	}
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+
+	err = os.Chmod(tmpSock, 0600)
+	if err == nil {
+		err = os.Rename(tmpSock, sockPath)
+	}
+	if err != nil {
+		ln.Close()
+		return nil, err
+	}
+
+	return ln, nil
+}
+
+// Serve listens on sockPath until ctx is done or the ttl expires, then
+// drops the keys and removes the socket. It fails when another agent
+// answers on sockPath.
+func (a *Agent) Serve(ctx context.Context, sockPath string) error {
+	// a socket nobody answers on was left by a crashed agent
+	conn, err := net.DialTimeout("unix", sockPath, time.Second)
+	if err == nil {
+		conn.Close()
+		return xerrors.Errorf("an agent is already running on %s", sockPath)
+	}
+	_ = os.Remove(sockPath)
+
+	ln, err := listen(sockPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(sockPath)
+
+	srv := rpc.NewServer()
+	err = srv.RegisterName(serviceName, &service{a})
+	if err != nil {
+		ln.Close()
+		return err
+	}
+
+	timer := time.NewTimer(time.Until(a.expire))
+	defer timer.Stop()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+			log.Println("agent ttl expired")
+		}
+		a.lock()
+		ln.Close()
+	}()
+
+	log.Println("agent listening on:", sockPath)
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if a.locked() {
+				return nil
+			}
+			return err
+		}
+
+		go srv.ServeConn(conn)
+	}
+}
+
+func (a *Agent) lock() {
+	a.lk.Lock()
+	defer a.lk.Unlock()
+
+	if a.w != nil {
+		a.w.Lock()
+		a.w = nil
+	}
+}
+
+func (a *Agent) locked() bool {
+	a.lk.Lock()
+	defer a.lk.Unlock()
+
+	return a.w == nil
+}
+
+func (a *Agent) unlocked() (*wallet.LocalWallet, error) {
+	a.lk.Lock()
+	defer a.lk.Unlock()
+
+	if a.w == nil || time.Now().After(a.expire) {
+		return nil, ErrLocked
+	}
+	return a.w, nil
+}
+
+// service is the rpc facade; only its exported methods are reachable.
+type service struct {
+	a *Agent
+}
+
+func (s *service) Sign(args SignArgs, reply *[]byte) error {
+	w, err := s.a.unlocked()
+	if err != nil {
+		return err
+	}
+
+	addr, err := address.NewAddress(args.Addr)
+	if err != nil {
+		return err
+	}
+
+	sig, err := w.WalletSign(context.TODO(), addr, args.Msg)
+	if err != nil {
+		return err
+	}
+
+	*reply = sig
+	return nil
+}
+
+func (s *service) List(_ struct{}, reply *[][]byte) error {
+	w, err := s.a.unlocked()
+	if err != nil {
+		return err
+	}
+
+	addrs, err := w.WalletList(context.TODO())
+	if err != nil {
+		return err
+	}
+
+	out := make([][]byte, 0, len(addrs))
+	for _, addr := range addrs {
+		out = append(out, addr.Bytes())
+	}
+
+	*reply = out
+	return nil
+}
+
+// Creds returns the gateway credentials of accessKey, or the only ones
+// when accessKey is empty.
+func (s *service) Creds(accessKey string, reply *Creds) error {
+	w, err := s.a.unlocked()
+	if err != nil {
+		return err
+	}
+
+	if accessKey == "" {
+		aks, err := w.CredsList(context.TODO())
+		if err != nil {
+			return err
+		}
+
+		switch len(aks) {
+		case 0:
+			return wallet.ErrNoCreds
+		case 1:
+			accessKey = aks[0]
+		default:
+			return xerrors.Errorf("%d gateway credentials in keystore, choose one with env ACCESS_KEY", len(aks))
+		}
+	}
+
+	sk, err := w.CredsExport(context.TODO(), accessKey)
+	if err != nil {
+		return err
+	}
+
+	*reply = Creds{AccessKey: accessKey, SecretKey: sk}
+	return nil
+}
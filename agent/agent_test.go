@@ -0,0 +1,263 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/memoio/memo-client/lib/address"
+	"github.com/memoio/memo-client/lib/crypto/signature"
+	"github.com/memoio/memo-client/lib/types"
+	"github.com/memoio/memo-client/wallet"
+	"golang.org/x/xerrors"
+)
+
+// memKeyStore keeps keys in memory, the file keystore's scrypt makes
+// every test take seconds.
+type memKeyStore struct {
+	keys map[string]types.KeyInfo
+	pws  map[string]string
+}
+
+func newMemKeyStore() *memKeyStore {
+	return &memKeyStore{
+		keys: make(map[string]types.KeyInfo),
+		pws:  make(map[string]string),
+	}
+}
+
+func (m *memKeyStore) List() ([]string, error) {
+	out := make([]string, 0, len(m.keys))
+	for name := range m.keys {
+		out = append(out, name)
+	}
+	return out, nil
+}
+
+func (m *memKeyStore) Get(name, pw string) (types.KeyInfo, error) {
+	ki, ok := m.keys[name]
+	if !ok || m.pws[name] != pw {
+		return types.KeyInfo{}, xerrors.Errorf("no key %s for this password", name)
+	}
+	return ki, nil
+}
+
+func (m *memKeyStore) Put(name, pw string, ki types.KeyInfo) error {
+	m.keys[name] = ki
+	m.pws[name] = pw
+	return nil
+}
+
+func (m *memKeyStore) Delete(name, _ string) error {
+	delete(m.keys, name)
+	delete(m.pws, name)
+	return nil
+}
+
+func (m *memKeyStore) Close() error {
+	return nil
+}
+
+// newTestAgent returns an agent on a fresh keystore with one secp256k1
+// key and one pair of gateway credentials.
+func newTestAgent(t *testing.T) (*Agent, address.Address, []byte) {
+	t.Helper()
+
+	ks := newMemKeyStore()
+
+	sk, err := signature.GenerateKey(types.Secp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skb, err := sk.Raw()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	w := wallet.New("pw", ks)
+	addr, err := w.WalletImport(ctx, &types.KeyInfo{Type: types.Secp256k1, SecretKey: skb})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = w.CredsImport(ctx, "ak1", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := New(ctx, wallet.New("pw", ks), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, err := sk.GetPublic().Raw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a, addr, pub
+}
+
+// serve runs a on sockPath until the test ends and returns the result of
+// Serve.
+func serve(t *testing.T, a *Agent, sockPath string) (context.CancelFunc, <-chan error) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- a.Serve(ctx, sockPath)
+	}()
+	return cancel, done
+}
+
+func dialAgent(t *testing.T, sockPath string, done <-chan error) *Client {
+	t.Helper()
+
+	for i := 0; i < 100; i++ {
+		select {
+		case err := <-done:
+			t.Fatalf("agent stopped: %v", err)
+		default:
+		}
+
+		c, err := Dial(sockPath)
+		if err == nil {
+			t.Cleanup(func() { c.Close() })
+			return c
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("agent never answered")
+	return nil
+}
+
+func TestClientRoundTrip(t *testing.T) {
+	a, addr, pub := newTestAgent(t)
+
+	sockPath := filepath.Join(t.TempDir(), "agent.sock")
+	cancel, done := serve(t, a, sockPath)
+	c := dialAgent(t, sockPath, done)
+
+	ctx := context.Background()
+	addrs, err := c.WalletList(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, got := range addrs {
+		if got == addr {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("%s not in listed %v", addr, addrs)
+	}
+
+	msg := bytes.Repeat([]byte{1}, 32)
+	sig, err := c.WalletSign(ctx, addr, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := signature.Verify(pub, msg, sig)
+	if err != nil || !ok {
+		t.Fatalf("agent signature does not verify: %v", err)
+	}
+
+	ak, sk, err := c.Creds(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ak != "ak1" || sk != "secret" {
+		t.Fatalf("creds %s:%s, want ak1:secret", ak, sk)
+	}
+
+	_, _, err = c.Creds(ctx, "ak2")
+	if err == nil {
+		t.Fatal("creds of an unknown access key")
+	}
+
+	cancel()
+	err = <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.locked() {
+		t.Fatal("keys kept after the agent stopped")
+	}
+
+	_, err = Dial(sockPath)
+	if err == nil {
+		t.Fatal("socket still answers after the agent stopped")
+	}
+}
+
+func TestServeRunning(t *testing.T) {
+	a, _, _ := newTestAgent(t)
+
+	sockPath := filepath.Join(t.TempDir(), "agent.sock")
+	_, done := serve(t, a, sockPath)
+	dialAgent(t, sockPath, done)
+
+	// stops the second agent should it serve after all
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	b, _, _ := newTestAgent(t)
+	err := b.Serve(ctx, sockPath)
+	if err == nil {
+		t.Fatal("second agent served on a running agent's socket")
+	}
+
+	// the running agent keeps its socket
+	dialAgent(t, sockPath, done)
+}
+
+func TestServeStale(t *testing.T) {
+	sockPath := filepath.Join(t.TempDir(), "agent.sock")
+
+	// a crashed agent leaves its socket behind
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	ln.Close()
+
+	a, _, _ := newTestAgent(t)
+	_, done := serve(t, a, sockPath)
+	c := dialAgent(t, sockPath, done)
+
+	_, err = c.WalletList(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExpiredAgent(t *testing.T) {
+	a, addr, _ := newTestAgent(t)
+	a.expire = time.Now()
+
+	sockPath := filepath.Join(t.TempDir(), "agent.sock")
+	_, done := serve(t, a, sockPath)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("agent still serving after its ttl")
+	}
+
+	err := (&service{a}).Sign(SignArgs{Addr: addr.Bytes(), Msg: []byte{1}}, new([]byte))
+	if err != ErrLocked {
+		t.Fatalf("sign after the ttl: %v, want %v", err, ErrLocked)
+	}
+}
@@ -0,0 +1,74 @@
+package agent
+
+import (
+	"context"
+	"net/rpc"
+
+	"github.com/memoio/memo-client/lib/address"
+)
+
+// Client talks to a running agent; it signs like LocalWallet without ever
+// seeing the password or the keys.
+type Client struct {
+	rc *rpc.Client
+}
+
+func Dial(sockPath string) (*Client, error) {
+	rc, err := rpc.Dial("unix", sockPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{rc: rc}, nil
+}
+
+func (c *Client) WalletSign(ctx context.Context, addr address.Address, msg []byte) ([]byte, error) {
+	var sig []byte
+	err := c.call(ctx, serviceName+".Sign", SignArgs{Addr: addr.Bytes(), Msg: msg}, &sig)
+	if err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+func (c *Client) WalletList(ctx context.Context) ([]address.Address, error) {
+	var res [][]byte
+	err := c.call(ctx, serviceName+".List", struct{}{}, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]address.Address, 0, len(res))
+	for _, b := range res {
+		addr, err := address.NewAddress(b)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, addr)
+	}
+	return out, nil
+}
+
+// Creds fetches the gateway credentials of accessKey, empty for the only ones.
+func (c *Client) Creds(ctx context.Context, accessKey string) (string, string, error) {
+	var res Creds
+	err := c.call(ctx, serviceName+".Creds", accessKey, &res)
+	if err != nil {
+		return "", "", err
+	}
+	return res.AccessKey, string(res.SecretKey), nil
+}
+
+func (c *Client) Close() error {
+	return c.rc.Close()
+}
+
+func (c *Client) call(ctx context.Context, method string, args, reply interface{}) error {
+	call := c.rc.Go(method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case res := <-call.Done:
+		return res.Error
+	}
+}
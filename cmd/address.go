@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/memoio/memo-client/lib/address"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+)
+
+var AddressCmd = &cli.Command{
+	Name:  "address",
+	Usage: "address utilities",
+	Subcommands: []*cli.Command{
+		addressInspectCmd,
+	},
+}
+
+var addressInspectCmd = &cli.Command{
+	Name:      "inspect",
+	Usage:     "validate an address and print all its forms",
+	ArgsUsage: "<Me address | eth address | 0x hex payload>",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "raw",
+			Usage: "treat 0x input as a hex payload, such as a public key",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		s := cctx.Args().First()
+		if s == "" {
+			return xerrors.New("address is nil")
+		}
+
+		var addr address.Address
+		var err error
+		checksum := "ok"
+		switch {
+		case cctx.Bool("raw"):
+			addr, err = address.NewFromHex(s)
+			checksum = "none"
+		case strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X"):
+			checked, ok := address.EthChecksumState(s)
+			switch {
+			case !checked:
+				checksum = "none"
+			case !ok:
+				checksum = "bad"
+			}
+			// show the forms of a bad checksum too, it fails below
+			addr, err = address.Parse(strings.ToLower(s))
+		default:
+			// Me addresses do not decode without a valid checksum
+			addr, err = address.Parse(s)
+		}
+		if err != nil {
+			return xerrors.Errorf("invalid address: %w", err)
+		}
+
+		fmt.Println("kind:    ", addr.Kind())
+		fmt.Println("network: ", address.CurrentNetwork)
+		fmt.Println("me:      ", addr)
+		fmt.Println("legacy:  ", addr.LegacyString())
+		fmt.Println("hex:     ", addr.Hex())
+
+		eth, err := addr.Eth()
+		if err == nil {
+			fmt.Println("eth:     ", eth)
+		}
+
+		fmt.Println("checksum:", checksum)
+		if checksum == "bad" {
+			return xerrors.Errorf("eth checksum of %s does not match, expect %s", s, eth)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/memoio/memo-client/agent"
+	"github.com/memoio/memo-client/lib/repo"
+	"github.com/memoio/memo-client/wallet"
+	"github.com/urfave/cli/v2"
+)
+
+const agentSockEnv = "MEMO_AGENT_SOCK"
+
+var agentFlag = &cli.StringFlag{
+	Name:    "agent",
+	Usage:   "sign and get gateway credentials through the agent on this unix socket",
+	EnvVars: []string{agentSockEnv},
+}
+
+var AgentCmd = &cli.Command{
+	Name:  "agent",
+	Usage: "run a signing agent that keeps the wallet unlocked",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "sock",
+			Usage: "unix socket to listen on, default memo-agent.sock in $XDG_RUNTIME_DIR",
+		},
+		&cli.DurationFlag{
+			Name:  "ttl",
+			Usage: "how long the keys stay unlocked",
+			Value: 30 * time.Minute,
+		},
+		passwordFileFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		rep, err := repo.NewFSRepo(cctx.String("repo"))
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = rep.Close()
+		}()
+
+		pw, err := getPassword(cctx, false)
+		if err != nil {
+			return err
+		}
+
+		w := wallet.New(pw, rep.KeyStore())
+		a, err := agent.New(cctx.Context, w, cctx.Duration("ttl"))
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := signal.NotifyContext(cctx.Context, os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+
+		sock := cctx.String("sock")
+		if sock == "" {
+			sock, err = agent.DefaultSockPath()
+			if err != nil {
+				return err
+			}
+		}
+		log.Printf("export %s=%s to sign through this agent\n", agentSockEnv, sock)
+
+		return a.Serve(ctx, sock)
+	},
+}
+
+// getSigner returns the agent client when --agent is set, otherwise it
+// opens the local wallet and asks for its password.
+func getSigner(cctx *cli.Context) (wallet.Signer, func(), error) {
+	sock := cctx.String(agentFlag.Name)
+	if sock != "" {
+		c, err := agent.Dial(sock)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c, func() { _ = c.Close() }, nil
+	}
+
+	rep, err := repo.NewFSRepo(cctx.String("repo"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pw, err := getPassword(cctx, false)
+	if err != nil {
+		_ = rep.Close()
+		return nil, nil, err
+	}
+
+	return wallet.New(pw, rep.KeyStore()), func() { _ = rep.Close() }, nil
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/memoio/memo-client/lib/address"
+	"github.com/memoio/memo-client/lib/chain"
+	"github.com/memoio/memo-client/lib/repo"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+)
+
+var fromFlag = &cli.StringFlag{
+	Name:  "from",
+	Usage: "eth address of the account key, default the one in 'address'",
+}
+
+// chainClient is shared by everything a command does on chain
+var chainClient *chain.Client
+
+// getChain connects to the chain of the current network's profile.
+func getChain(cctx *cli.Context) (*chain.Client, error) {
+	if chainClient != nil {
+		return chainClient, nil
+	}
+
+	p, err := getProfile(cctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := chain.Dial(cctx.Context, p)
+	if err != nil {
+		return nil, err
+	}
+
+	chainClient = c
+	return c, nil
+}
+
+// getProfile loads the chain profile of the current network without
+// dialing the node.
+func getProfile(cctx *cli.Context) (*chain.Profile, error) {
+	repoDir := cctx.String("repo")
+	if repoDir == "" {
+		repoDir = "./"
+	}
+
+	return chain.LoadProfile(repoDir, address.CurrentNetwork.String())
+}
+
+// account is the chain account a command transacts with.
+type account struct {
+	c    *chain.Client
+	m    *chain.TxManager
+	opts *bind.TransactOpts
+
+	closers []func()
+}
+
+// openAccount opens the account of --from with the tx manager on the repo
+// state; its opts only sign, txs are sent with transact.
+func openAccount(cctx *cli.Context) (*account, error) {
+	from, err := accountAddress(cctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := getChain(cctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &account{c: c}
+
+	rep, err := repo.NewFSRepo(cctx.String("repo"))
+	if err != nil {
+		return nil, err
+	}
+	a.closers = append(a.closers, func() { _ = rep.Close() })
+	a.m = chain.NewTxManager(c, rep.StateStore())
+
+	signer, closer, err := getSigner(cctx)
+	if err != nil {
+		a.Close()
+		return nil, err
+	}
+	a.closers = append(a.closers, closer)
+
+	a.opts, err = c.TransactOpts(cctx.Context, signer, ethcommon.BytesToAddress(from.Bytes()))
+	if err != nil {
+		a.Close()
+		return nil, err
+	}
+	a.opts.NoSend = true
+
+	return a, nil
+}
+
+func (a *account) Close() {
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		a.closers[i]()
+	}
+}
+
+// transact sends the tx of build at the next nonce, waits for it to be
+// mined and prints its receipt.
+func (a *account) transact(cctx *cli.Context, build func(*bind.TransactOpts) (*types.Transaction, error)) error {
+	gaps, err := a.m.SyncNonce(cctx.Context, a.opts.From)
+	if err != nil {
+		return err
+	}
+	if len(gaps) > 0 {
+		fmt.Printf("nonces %v of %s were never sent, reusing them\n", gaps, a.opts.From)
+	}
+
+	tx, err := a.m.Transact(cctx.Context, a.opts, build)
+	if err != nil {
+		return err
+	}
+	fmt.Println("tx:      ", tx.Hash())
+
+	r, err := a.m.Wait(cctx.Context, tx.Hash())
+	if r != nil {
+		printReceipt(r)
+	}
+	if err == chain.ErrTxUnknown {
+		return xerrors.Errorf("%w, it stays tracked: follow it with 'tx wait' or re-send it with 'tx speedup'", err)
+	}
+	return err
+}
+
+// ethAddress parses an eth or Me address into an eth address.
+func ethAddress(s string) (ethcommon.Address, error) {
+	addr, err := address.Parse(s)
+	if err != nil {
+		return ethcommon.Address{}, err
+	}
+
+	b, err := addr.EthBytes()
+	if err != nil {
+		return ethcommon.Address{}, xerrors.Errorf("%s: %w", s, err)
+	}
+	return ethcommon.BytesToAddress(b), nil
+}
+
+func printReceipt(r *types.Receipt) {
+	status := "ok"
+	if r.Status != types.ReceiptStatusSuccessful {
+		status = "failed"
+	}
+
+	fmt.Println("status:  ", status)
+	fmt.Println("block:   ", r.BlockNumber)
+	fmt.Println("gas used:", r.GasUsed)
+	fmt.Println("logs:    ", len(r.Logs))
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/memoio/memo-client/agent"
+	"github.com/memoio/memo-client/lib"
+	"github.com/memoio/memo-client/lib/repo"
+	"github.com/memoio/memo-client/wallet"
+	miniogo "github.com/minio/minio-go/v7"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+	"golang.org/x/xerrors"
+)
+
+var CredsCmd = &cli.Command{
+	Name:  "creds",
+	Usage: "manage gateway access keys stored in the keystore",
+	Subcommands: []*cli.Command{
+		credsAddCmd,
+		credsListCmd,
+		credsRmCmd,
+	},
+}
+
+var credsAddCmd = &cli.Command{
+	Name:      "add",
+	Usage:     "store an access key, the secret key is read from prompt or stdin",
+	ArgsUsage: "<access key>",
+	Flags: []cli.Flag{
+		passwordFileFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		ak := cctx.Args().First()
+		if ak == "" {
+			return xerrors.New("access key is nil")
+		}
+
+		sk, err := readSecretKey()
+		if err != nil {
+			return err
+		}
+
+		return withWallet(cctx, func(w *wallet.LocalWallet) error {
+			return w.CredsImport(cctx.Context, ak, []byte(sk))
+		})
+	},
+}
+
+var credsListCmd = &cli.Command{
+	Name:  "list",
+	Usage: "list stored access keys",
+	Action: func(cctx *cli.Context) error {
+		rep, err := repo.NewFSRepo(cctx.String("repo"))
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = rep.Close()
+		}()
+
+		aks, err := wallet.New("", rep.KeyStore()).CredsList(cctx.Context)
+		if err != nil {
+			return err
+		}
+
+		for _, ak := range aks {
+			fmt.Println(ak)
+		}
+		return nil
+	},
+}
+
+var credsRmCmd = &cli.Command{
+	Name:      "rm",
+	Usage:     "remove a stored access key",
+	ArgsUsage: "<access key>",
+	Flags: []cli.Flag{
+		passwordFileFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		ak := cctx.Args().First()
+		if ak == "" {
+			return xerrors.New("access key is nil")
+		}
+
+		return withWallet(cctx, func(w *wallet.LocalWallet) error {
+			return w.CredsDelete(cctx.Context, ak)
+		})
+	},
+}
+
+// withWallet opens the local wallet with its password for fn.
+func withWallet(cctx *cli.Context, fn func(w *wallet.LocalWallet) error) error {
+	rep, err := repo.NewFSRepo(cctx.String("repo"))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = rep.Close()
+	}()
+
+	pw, err := getPassword(cctx, false)
+	if err != nil {
+		return err
+	}
+
+	return fn(wallet.New(pw, rep.KeyStore()))
+}
+
+func readSecretKey() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		return promptPassword(fd, "Enter secret key: ")
+	}
+
+	buf, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(buf)), nil
+}
+
+// newClient connects to the gateway, decrypting the stored credentials
+// with the wallet password when they are not given in env.
+func newClient(cctx *cli.Context) (*miniogo.Client, error) {
+	useCreds(cctx)
+	return lib.New()
+}
+
+// useCreds points lib at the gateway credentials of the repo: through the
+// agent when --agent is set, otherwise asking for the wallet password.
+func useCreds(cctx *cli.Context) {
+	lib.RepoPath = cctx.String("repo")
+	lib.Password = func() (string, error) {
+		return getPassword(cctx, false)
+	}
+
+	sock := cctx.String(agentFlag.Name)
+	if sock == "" {
+		lib.AgentCreds = nil
+		return
+	}
+
+	lib.AgentCreds = func(accessKey string) (string, string, error) {
+		c, err := agent.Dial(sock)
+		if err != nil {
+			return "", "", err
+		}
+		defer c.Close()
+
+		return c.Creds(cctx.Context, accessKey)
+	}
+}
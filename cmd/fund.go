@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/memoio/memo-client/lib/chain"
+	miniogo "github.com/minio/minio-go/v7"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+)
+
+const (
+	fundPollInterval = 5 * time.Second
+	fundTimeout      = 2 * time.Minute
+)
+
+// autoFund deposits what the gateway balance of bucket lacks for price,
+// plus the configured margin, to the storage account of owner, which the
+// bucket is charged to, and waits until the gateway has seen it. The
+// deposit is paid by the --from account.
+func autoFund(cctx *cli.Context, client *miniogo.Client, bucket string, owner common.Address, price, balance *big.Int) error {
+	a, err := openAccount(cctx)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	cfg := a.c.Profile().Fund
+	if max := cctx.String("auto-fund-max"); max != "" {
+		cfg.Max = max
+	}
+
+	shortfall := new(big.Int).Sub(price, balance)
+	amount, err := cfg.FundAmount(shortfall)
+	if err != nil {
+		return err
+	}
+
+	owned, err := a.c.BalanceOf(cctx.Context, a.opts.From)
+	if err != nil {
+		return err
+	}
+	if owned.Cmp(amount) < 0 {
+		return xerrors.Errorf("deposit of %s MEMO is above the balance %s MEMO of %s", chain.FormatAmount(amount), chain.FormatAmount(owned), a.opts.From)
+	}
+
+	fmt.Printf("balance is short by %s MEMO, depositing %s MEMO to %s\n", chain.FormatAmount(shortfall), chain.FormatAmount(amount), owner)
+
+	store := a.c.Profile().Store
+	allowance, err := a.c.Allowance(cctx.Context, a.opts.From, store)
+	if err != nil {
+		return err
+	}
+
+	if allowance.Cmp(amount) < 0 {
+		err = a.transact(cctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return a.c.Approve(opts, store, amount)
+		})
+		if err != nil {
+			return xerrors.Errorf("approve: %w", err)
+		}
+	}
+
+	err = a.transact(cctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return a.c.StoreDeposit(opts, owner, amount, "")
+	})
+	if err != nil {
+		return xerrors.Errorf("deposit: %w", err)
+	}
+
+	return waitGatewayBalance(cctx.Context, client, bucket, price)
+}
+
+// waitGatewayBalance polls until the gateway balance of bucket covers price.
+func waitGatewayBalance(ctx context.Context, client *miniogo.Client, bucket string, price *big.Int) error {
+	ctx, cancel := context.WithTimeout(ctx, fundTimeout)
+	defer cancel()
+
+	for {
+		balance, err := client.GetBalanceInfo(ctx, bucket)
+		if err == nil {
+			b, ok := new(big.Int).SetString(balance, 10)
+			if ok && b.Cmp(price) >= 0 {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return xerrors.Errorf("gateway balance still below %d after the deposit: %w", price, ctx.Err())
+		case <-time.After(fundPollInterval):
+		}
+	}
+}
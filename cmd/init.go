@@ -6,11 +6,10 @@ import (
 	"log"
 	"os"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/memoio/memo-client/lib/crypto/signature"
+	"github.com/memoio/memo-client/lib/crypto/signature/bls"
 	"github.com/memoio/memo-client/lib/repo"
 	"github.com/memoio/memo-client/lib/types"
-	"github.com/memoio/memo-client/lib/utils"
 	"github.com/memoio/memo-client/wallet"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/xerrors"
@@ -20,6 +19,10 @@ import (
 var InitCmd = &cli.Command{
 	Name:  "init",
 	Usage: "init a memo client",
+	Flags: []cli.Flag{
+		blsDeriveFlag,
+		passwordFileFlag,
+	},
 	Action: func(ctx *cli.Context) error {
 		log.Println("Initializing memo client")
 		repoDir := "./"
@@ -33,6 +36,19 @@ var InitCmd = &cli.Command{
 			return xerrors.Errorf("repo at '%s' is already initialized", repoDir)
 		}
 
+		// asked before anything is on disk, a mistyped confirmation must
+		// not leave a half made repo behind
+		pw, err := getPassword(ctx, true)
+		if err != nil {
+			return err
+		}
+
+		switch ctx.String(blsDeriveFlag.Name) {
+		case blsDeriveLegacy, blsDeriveEIP2333:
+		default:
+			return xerrors.Errorf("unknown bls derivation %s", ctx.String(blsDeriveFlag.Name))
+		}
+
 		log.Println("initializing repo at: ", repoDir)
 
 		rep, err := repo.NewFSRepo(repoDir)
@@ -43,9 +59,9 @@ var InitCmd = &cli.Command{
 		defer func() {
 			_ = rep.Close()
 		}()
-		pw := ctx.String("passwd")
+
 		sk := ctx.String("sk")
-		err = create(ctx.Context, rep, pw, sk)
+		err = create(ctx.Context, rep, pw, sk, ctx.String(blsDeriveFlag.Name))
 
 		if err != nil {
 			log.Printf("fail initializing node %s", err)
@@ -55,7 +71,18 @@ var InitCmd = &cli.Command{
 	},
 }
 
-func create(ctx context.Context, r repo.Repo, password, sk string) error {
+const (
+	blsDeriveLegacy  = "legacy"
+	blsDeriveEIP2333 = "eip2333"
+)
+
+var blsDeriveFlag = &cli.StringFlag{
+	Name:  "bls-derive",
+	Usage: "how the bls key is derived from the account key: legacy or eip2333",
+	Value: blsDeriveLegacy,
+}
+
+func create(ctx context.Context, r repo.Repo, password, sk, blsDerive string) error {
 	w := wallet.New(password, r.KeyStore())
 
 	var sBytes []byte
@@ -92,7 +119,10 @@ func create(ctx context.Context, r repo.Repo, password, sk string) error {
 		return err
 	}
 
-	wa := common.BytesToAddress(utils.ToEthAddress(addr.Bytes()))
+	wa, err := addr.Eth()
+	if err != nil {
+		return err
+	}
 
 	if sk == "" {
 		log.Println("generated wallet address: ", wa)
@@ -100,20 +130,16 @@ func create(ctx context.Context, r repo.Repo, password, sk string) error {
 		log.Println("import wallet address: ", wa)
 	}
 
-	err = os.WriteFile("address", []byte(wa.String()), 0600)
+	err = os.WriteFile("address", []byte(wa), 0600)
 	if err != nil {
 		log.Println(err)
 		return err
 	}
 	log.Println("generating bls key...")
 
-	blsSeed := make([]byte, len(sBytes)+1)
-	copy(blsSeed[:len(sBytes)], sBytes)
-	blsSeed[len(sBytes)] = byte(types.BLS)
-	blsByte := blake3.Sum256(blsSeed)
-	blsKey := &types.KeyInfo{
-		SecretKey: blsByte[:],
-		Type:      types.BLS,
+	blsKey, err := deriveBLSKey(sBytes, blsDerive)
+	if err != nil {
+		return err
 	}
 
 	blsAddr, err := w.WalletImport(ctx, blsKey)
@@ -139,6 +165,38 @@ func create(ctx context.Context, r repo.Repo, password, sk string) error {
 	return nil
 }
 
+// deriveBLSKey derives the bls key from the secp256k1 account key, either
+// as blake3(sk || BLS) or as the EIP-2333 signing key with sk as seed.
+func deriveBLSKey(sk []byte, scheme string) (*types.KeyInfo, error) {
+	switch scheme {
+	case blsDeriveLegacy:
+		blsSeed := make([]byte, len(sk)+1)
+		copy(blsSeed[:len(sk)], sk)
+		blsSeed[len(sk)] = byte(types.BLS)
+		blsByte := blake3.Sum256(blsSeed)
+		return &types.KeyInfo{
+			SecretKey: blsByte[:],
+			Type:      types.BLS,
+		}, nil
+	case blsDeriveEIP2333:
+		privkey, err := bls.DeriveKey(sk, bls.SigningKeyPath)
+		if err != nil {
+			return nil, err
+		}
+
+		blsByte, err := privkey.Raw()
+		if err != nil {
+			return nil, err
+		}
+		return &types.KeyInfo{
+			SecretKey: blsByte,
+			Type:      types.BLS,
+		}, nil
+	default:
+		return nil, xerrors.Errorf("unknown bls derivation %s", scheme)
+	}
+}
+
 // var InitBucketCmd = &cli.Command{
 // 	Name:  "initb",
 // 	Usage: "init bucket",
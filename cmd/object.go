@@ -10,13 +10,13 @@ import (
 	"math/big"
 	"os"
 	"strings"
+	"time"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/memoio/memo-client/lib"
 	"github.com/memoio/memo-client/lib/address"
-	"github.com/memoio/memo-client/lib/repo"
+	"github.com/memoio/memo-client/lib/auth"
 	"github.com/memoio/memo-client/wallet"
 	miniogo "github.com/minio/minio-go/v7"
 	"github.com/urfave/cli/v2"
@@ -37,6 +37,25 @@ var PutObjectCmd = &cli.Command{
 			Usage: "time to storage(day)(min=100, max=1000)",
 			Value: 100,
 		},
+		&cli.BoolFlag{
+			Name:  "legacy-sign",
+			Usage: "sign only the storage time instead of the typed upload auth, for gateways without it; this signature can be replayed",
+		},
+		&cli.DurationFlag{
+			Name:  "auth-ttl",
+			Usage: "how long the upload auth stays valid",
+			Value: time.Hour,
+		},
+		&cli.BoolFlag{
+			Name:  "auto-fund",
+			Usage: "approve and deposit the shortfall when the balance is below the price",
+		},
+		&cli.StringFlag{
+			Name:  "auto-fund-max",
+			Usage: "most MEMO to deposit at once, default the fund max of the chain profile",
+		},
+		passwordFileFlag,
+		agentFlag,
 	},
 	Action: func(cctx *cli.Context) error {
 		// get parameters
@@ -64,7 +83,7 @@ var PutObjectCmd = &cli.Command{
 		}
 		ssize := big.NewInt(fileinfo.Size())
 
-		client, err := lib.New()
+		client, err := newClient(cctx)
 		if err != nil {
 			return err
 		}
@@ -84,17 +103,27 @@ var PutObjectCmd = &cli.Command{
 		balancei := new(big.Int)
 		balancei.SetString(balance, 10)
 
-		if balancei.Cmp(amount) < 0 {
+		short := balancei.Cmp(amount) < 0
+		if short && !cctx.Bool("auto-fund") {
 			return xerrors.Errorf("balance not enough, amount: %d, balance: %d", amount, balancei)
 		}
 
 		log.Printf("upload info: size is %dB, time is %dday, cost is %d automemo\n", fileinfo.Size(), date, amount)
 
+		// known before asking, the upload fails without it
+		var chainID *big.Int
+		if !cctx.Bool("legacy-sign") {
+			chainID, err = uploadChainID(cctx)
+			if err != nil {
+				return xerrors.Errorf("no chain id for the upload auth, use --legacy-sign for gateways without it: %w", err)
+			}
+		}
+
 		// ask whether to upload
 		upload := false
 
 		for i := 0; i < 3; i++ {
-			res, err := ask4confirm()
+			res, err := ask4confirm("upload")
 			if err == nil {
 				upload = res
 				break
@@ -106,23 +135,21 @@ var PutObjectCmd = &cli.Command{
 			return nil
 		}
 
-		repoDir := cctx.String("repo")
+		// funded only once the upload is confirmed
+		if short {
+			err = autoFund(cctx, client, bucket, ethcommon.HexToAddress(bucket), amount, balancei)
+			if err != nil {
+				return xerrors.Errorf("auto fund: %w", err)
+			}
+		}
 
-		rep, err := repo.NewFSRepo(repoDir)
+		signer, closer, err := getSigner(cctx)
 		if err != nil {
-			return nil
+			return err
 		}
-
-		defer func() {
-			_ = rep.Close()
-		}()
-
-		pw := cctx.String("passwd")
+		defer closer()
 
 		maddr := ethcommon.HexToAddress(bucket)
-		if err != nil {
-			return nil
-		}
 
 		srcaddr, err := address.NewAddress(maddr.Bytes())
 		if err != nil {
@@ -130,33 +157,6 @@ var PutObjectCmd = &cli.Command{
 		}
 		log.Println("srcaddr ", srcaddr)
 
-		w := wallet.New(pw, rep.KeyStore())
-
-		// get sk
-		sks, err := w.WalletExport(cctx.Context, srcaddr, "")
-		if err != nil {
-			return err
-		}
-
-		log.Printf("%x\n", string(sks.SecretKey))
-		sk := hex.EncodeToString(sks.SecretKey)
-
-		privateKey, err := crypto.HexToECDSA(sk)
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		sdata := []byte(dated.String())
-		hash := crypto.Keccak256Hash(sdata)
-		log.Println(hash.Hex())
-
-		signature, err := crypto.Sign(hash.Bytes(), privateKey)
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		log.Println("sign: ", hexutil.Encode(signature))
-
 		log.Println(fileinfo.Name())
 		object := fileinfo.Name()
 		data, err := os.ReadFile(path)
@@ -165,10 +165,31 @@ var PutObjectCmd = &cli.Command{
 		}
 		r := bytes.NewBuffer(data)
 
-		metadata := make(map[string]string)
+		var metadata map[string]string
+		if cctx.Bool("legacy-sign") {
+			// the legacy signature covers only the storage time and can be
+			// replayed, so it is sent only when asked for
+			sdata := []byte(dated.String())
+			hash := crypto.Keccak256Hash(sdata)
+			log.Println(hash.Hex())
+
+			signature, err := signer.WalletSign(cctx.Context, srcaddr, hash.Bytes())
+			if err != nil {
+				return err
+			}
+
+			log.Println("sign: ", hexutil.Encode(signature))
 
-		metadata["sign"] = hexutil.Encode(signature)
-		metadata["date"] = dated.String()
+			metadata = map[string]string{
+				"sign": hexutil.Encode(signature),
+				"date": dated.String(),
+			}
+		} else {
+			metadata, err = signUpload(cctx, signer, srcaddr, maddr, object, data, date, chainID)
+			if err != nil {
+				return err
+			}
+		}
 
 		log.Println("metadata: ", metadata)
 
@@ -200,6 +221,8 @@ var GetObjectCmd = &cli.Command{
 			Name:  "path",
 			Usage: "stored path of file",
 		},
+		passwordFileFlag,
+		agentFlag,
 	},
 	Action: func(cctx *cli.Context) error {
 		buf, err := os.ReadFile("address")
@@ -211,7 +234,7 @@ var GetObjectCmd = &cli.Command{
 		object := cctx.String("object")
 		path := cctx.String("path")
 
-		client, err := lib.New()
+		client, err := newClient(cctx)
 		if err != nil {
 			return err
 		}
@@ -243,6 +266,10 @@ var GetObjectCmd = &cli.Command{
 var ListObjectCmd = &cli.Command{
 	Name:  "list",
 	Usage: "list objects",
+	Flags: []cli.Flag{
+		passwordFileFlag,
+		agentFlag,
+	},
 	Action: func(ctx *cli.Context) error {
 		buf, err := os.ReadFile("address")
 		if err != nil {
@@ -251,7 +278,7 @@ var ListObjectCmd = &cli.Command{
 
 		bucket := string(buf)
 
-		client, err := lib.New()
+		client, err := newClient(ctx)
 		if err != nil {
 			return err
 		}
@@ -265,6 +292,47 @@ var ListObjectCmd = &cli.Command{
 	},
 }
 
+// signUpload signs an EIP-712 authorization binding the bucket, object,
+// content and chain, and returns it as object metadata.
+func signUpload(cctx *cli.Context, signer wallet.Signer, addr address.Address, bucket ethcommon.Address, object string, data []byte, days int64, chainID *big.Int) (map[string]string, error) {
+	ua, err := auth.NewUpload(bucket, object, data, days, chainID, cctx.Duration("auth-ttl"))
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := ua.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := signer.WalletSign(cctx.Context, addr, hash)
+	if err != nil {
+		return nil, err
+	}
+	sig[len(sig)-1] += 27
+
+	return ua.Metadata(sig)
+}
+
+// uploadChainID returns the chain id the upload auth is bound to: the
+// profile's when set, else the node's.
+func uploadChainID(cctx *cli.Context) (*big.Int, error) {
+	p, err := getProfile(cctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.ChainID != 0 {
+		return new(big.Int).SetUint64(p.ChainID), nil
+	}
+
+	c, err := getChain(cctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.ChainID(), nil
+}
+
 func fileMD5(path string) (string, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -277,10 +345,15 @@ func fileMD5(path string) (string, error) {
 	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
-func ask4confirm() (bool, error) {
+func ask4confirm(what string) (bool, error) {
+	return ask4confirmTo(os.Stdout, what)
+}
+
+// ask4confirmTo prompts on w, for commands whose stdout is their output.
+func ask4confirmTo(w io.Writer, what string) (bool, error) {
 	var s string
 
-	fmt.Printf("whether to upload(y/N): ")
+	fmt.Fprintf(w, "whether to %s(y/N): ", what)
 	_, err := fmt.Scan(&s)
 	if err != nil {
 		return false, err
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+	"golang.org/x/xerrors"
+)
+
+const passwordEnv = "MEMO_PASSWORD"
+
+var passwordFileFlag = &cli.StringFlag{
+	Name:  "password-file",
+	Usage: "read wallet password from file, instead of env " + passwordEnv + " or prompt",
+}
+
+// the password is read at most once per run
+var passwordCache *string
+
+// getPassword returns the wallet password, taken in order from
+// --password-file, env MEMO_PASSWORD and an interactive no-echo prompt.
+// confirm asks for the password twice when prompting, used on create.
+func getPassword(cctx *cli.Context, confirm bool) (string, error) {
+	if passwordCache != nil && !confirm {
+		return *passwordCache, nil
+	}
+
+	pw, err := readPassword(cctx, confirm)
+	if err != nil {
+		return "", err
+	}
+
+	passwordCache = &pw
+	return pw, nil
+}
+
+func readPassword(cctx *cli.Context, confirm bool) (string, error) {
+	pf := cctx.String(passwordFileFlag.Name)
+	if pf != "" {
+		buf, err := os.ReadFile(pf)
+		if err != nil {
+			return "", xerrors.Errorf("read password file %s: %w", pf, err)
+		}
+		return strings.TrimRight(string(buf), "\r\n"), nil
+	}
+
+	pw, ok := os.LookupEnv(passwordEnv)
+	if ok {
+		return pw, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", xerrors.Errorf("no password: use --%s or env %s when stdin is not a terminal", passwordFileFlag.Name, passwordEnv)
+	}
+
+	pw, err := promptPassword(fd, "Enter wallet password: ")
+	if err != nil {
+		return "", err
+	}
+
+	if confirm {
+		again, err := promptPassword(fd, "Confirm wallet password: ")
+		if err != nil {
+			return "", err
+		}
+		if pw != again {
+			return "", xerrors.New("passwords do not match")
+		}
+	}
+
+	return pw, nil
+}
+
+func promptPassword(fd int, prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	buf, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", xerrors.Errorf("read password: %w", err)
+	}
+	return string(buf), nil
+}
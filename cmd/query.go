@@ -1,11 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"os"
+	"strings"
+
+	"github.com/memoio/memo-client/lib/chain"
 
-	"github.com/memoio/memo-client/lib"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/xerrors"
 )
@@ -23,9 +26,11 @@ var QueryCmd = &cli.Command{
 			Name:  "path",
 			Usage: "path of file",
 		},
+		passwordFileFlag,
+		agentFlag,
 	},
 	Action: func(cctx *cli.Context) error {
-		client, err := lib.New()
+		client, err := newClient(cctx)
 		if err != nil {
 			return err
 		}
@@ -66,25 +71,81 @@ var QueryCmd = &cli.Command{
 
 var GetBalanceInfoCmd = &cli.Command{
 	Name:  "balance",
-	Usage: "get balance info",
+	Usage: "get balance info from the gateway and the chain",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "no-chain",
+			Usage: "only ask the gateway",
+		},
+		passwordFileFlag,
+		agentFlag,
+	},
 	Action: func(ctx *cli.Context) error {
-		client, err := lib.New()
+		buf, err := os.ReadFile("address")
 		if err != nil {
 			return err
 		}
 
-		buf, err := os.ReadFile("address")
+		address := strings.TrimSpace(string(buf))
+		balance, err := gatewayBalance(ctx, address)
+		if err != nil {
+			fmt.Println("gateway:        ", err)
+		} else {
+			fmt.Printf("gateway:         %s automemo\n", balance)
+		}
+
+		if ctx.Bool("no-chain") {
+			return nil
+		}
+
+		owner, err := ethAddress(address)
 		if err != nil {
 			return err
 		}
 
-		address := string(buf)
-		balance, err := client.GetBalanceInfo(ctx.Context, address)
+		c, err := getChain(ctx)
 		if err != nil {
-			fmt.Println(err)
+			return err
 		}
-		fmt.Printf("%s automemo\n", balance)
+
+		printChainBalance(ctx, "native:         ", "", func(cctx context.Context) (*big.Int, error) {
+			return c.BalanceAt(cctx, owner, nil)
+		})
+		printChainBalance(ctx, "MEMO:           ", "MEMO", func(cctx context.Context) (*big.Int, error) {
+			return c.BalanceOf(cctx, owner)
+		})
+		printChainBalance(ctx, "allowance:      ", "MEMO", func(cctx context.Context) (*big.Int, error) {
+			return c.Allowance(cctx, owner, c.Profile().Store)
+		})
+		// a lifetime total, what is left of it is only known to the gateway
+		printChainBalance(ctx, "total deposited:", "MEMO", func(cctx context.Context) (*big.Int, error) {
+			return c.Deposited(cctx, owner)
+		})
 
 		return nil
 	},
 }
+
+func gatewayBalance(cctx *cli.Context, address string) (string, error) {
+	client, err := newClient(cctx)
+	if err != nil {
+		return "", err
+	}
+	return client.GetBalanceInfo(cctx.Context, address)
+}
+
+// printChainBalance prints what get returns in unit, or its error, so
+// one failed query does not hide the others.
+func printChainBalance(cctx *cli.Context, label, unit string, get func(context.Context) (*big.Int, error)) {
+	v, err := get(cctx.Context)
+	if err != nil {
+		fmt.Println(label, err)
+		return
+	}
+
+	if unit == "" {
+		fmt.Printf("%s %s (%s wei)\n", label, chain.FormatAmount(v), v)
+		return
+	}
+	fmt.Printf("%s %s %s (%s automemo)\n", label, chain.FormatAmount(v), unit, v)
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"encoding/binary"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/memoio/memo-client/lib"
+	"github.com/memoio/memo-client/lib/address"
+	"github.com/memoio/memo-client/lib/pb"
+	"github.com/memoio/memo-client/lib/repo"
+	"github.com/memoio/memo-client/lib/types/store"
+	"github.com/memoio/memo-client/wallet"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+)
+
+// state key of the role id assigned at registration
+var roleIDKey = []byte("roleID")
+
+var RegisterCmd = &cli.Command{
+	Name:  "register",
+	Usage: "register as user with the wallet's account and bls keys",
+	Flags: []cli.Flag{
+		&cli.Uint64Flag{
+			Name:     "group",
+			Usage:    "group id to join",
+			Required: true,
+		},
+		fromFlag,
+		&cli.StringFlag{
+			Name:  "bls",
+			Usage: "Me address of the bls key, needed when the wallet has several",
+		},
+		&cli.StringFlag{
+			Name:  "desc",
+			Usage: "description of the role",
+		},
+		passwordFileFlag,
+		agentFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		rep, err := repo.NewFSRepo(cctx.String("repo"))
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = rep.Close()
+		}()
+
+		id, err := loadRoleID(rep.StateStore())
+		if err == nil {
+			return xerrors.Errorf("already registered as role %d", id)
+		}
+		if err != store.ErrNotFound {
+			return err
+		}
+
+		from, err := accountAddress(cctx)
+		if err != nil {
+			return err
+		}
+
+		blsAddr, err := blsAddress(cctx, rep)
+		if err != nil {
+			return err
+		}
+
+		ri := &pb.RoleInfo{
+			Type:           pb.RoleInfo_User,
+			GroupID:        cctx.Uint64("group"),
+			ChainVerifyKey: from.Bytes(),
+			BlsVerifyKey:   blsAddr.Bytes(),
+			Desc:           []byte(cctx.String("desc")),
+		}
+
+		msg, err := lib.RoleInfoHash(ri)
+		if err != nil {
+			return err
+		}
+
+		signer, closer, err := getSigner(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		sig, err := signer.WalletSign(cctx.Context, from, msg)
+		if err != nil {
+			return xerrors.Errorf("sign with account key: %w", err)
+		}
+
+		blsSig, err := signer.WalletSign(cctx.Context, blsAddr, msg)
+		if err != nil {
+			return xerrors.Errorf("sign with bls key: %w", err)
+		}
+
+		client, err := newClient(cctx)
+		if err != nil {
+			return err
+		}
+
+		nri, err := lib.RegisterRole(cctx.Context, client, ri, sig, blsSig)
+		if err != nil {
+			return err
+		}
+
+		err = storeRoleID(rep.StateStore(), nri.GetRoleID())
+		if err != nil {
+			return xerrors.Errorf("registered as role %d but failed to save it: %w", nri.GetRoleID(), err)
+		}
+
+		printRoleInfo(nri)
+		return nil
+	},
+}
+
+// accountAddress returns --from or the eth address written by init.
+func accountAddress(cctx *cli.Context) (address.Address, error) {
+	s := cctx.String("from")
+	if s == "" {
+		buf, err := os.ReadFile("address")
+		if err != nil {
+			return address.Undef, xerrors.Errorf("no --from and no address file: %w", err)
+		}
+		s = strings.TrimSpace(string(buf))
+	}
+
+	addr, err := address.Parse(s)
+	if err != nil {
+		return address.Undef, err
+	}
+
+	if addr.Kind() != address.KindEth {
+		return address.Undef, xerrors.Errorf("%s is not an eth address", s)
+	}
+	return addr, nil
+}
+
+// blsAddress returns --bls or the only bls key of the wallet.
+func blsAddress(cctx *cli.Context, rep repo.Repo) (address.Address, error) {
+	if s := cctx.String("bls"); s != "" {
+		addr, err := address.NewFromString(s)
+		if err != nil {
+			return address.Undef, err
+		}
+
+		if addr.Kind() != address.KindBLS {
+			return address.Undef, xerrors.Errorf("%s is not a bls key", s)
+		}
+		return addr, nil
+	}
+
+	// listing needs no password
+	addrs, err := wallet.New("", rep.KeyStore()).WalletList(cctx.Context)
+	if err != nil {
+		return address.Undef, err
+	}
+
+	res := address.Undef
+	for _, addr := range addrs {
+		if addr.Kind() != address.KindBLS {
+			continue
+		}
+
+		if res != address.Undef {
+			return address.Undef, xerrors.New("several bls keys in wallet, choose one with --bls")
+		}
+		res = addr
+	}
+
+	if res == address.Undef {
+		return address.Undef, xerrors.New("no bls key in wallet")
+	}
+	return res, nil
+}
+
+func loadRoleID(ds store.KVStore) (uint64, error) {
+	val, err := ds.Get(roleIDKey)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(val) != 8 {
+		return 0, xerrors.Errorf("invalid role id in state: %x", val)
+	}
+	return binary.BigEndian.Uint64(val), nil
+}
+
+func storeRoleID(ds store.KVStore, id uint64) error {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, id)
+	return ds.Put(roleIDKey, buf)
+}
+
+// roleIDArg returns the role id argument, or the registered one.
+func roleIDArg(cctx *cli.Context) (uint64, error) {
+	if arg := cctx.Args().First(); arg != "" {
+		id, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return 0, xerrors.Errorf("invalid role id: %w", err)
+		}
+		return id, nil
+	}
+
+	rep, err := repo.NewFSRepo(cctx.String("repo"))
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		_ = rep.Close()
+	}()
+
+	id, err := loadRoleID(rep.StateStore())
+	if err == store.ErrNotFound {
+		return 0, xerrors.New("no role id given and not registered")
+	}
+	return id, err
+}
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/memoio/memo-client/lib"
+	"github.com/memoio/memo-client/lib/address"
+	"github.com/memoio/memo-client/lib/pb"
+	"github.com/memoio/memo-client/lib/repo"
+	"github.com/memoio/memo-client/wallet"
+	b58 "github.com/mr-tron/base58/base58"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+)
+
+var netInfoFlag = &cli.BoolFlag{
+	Name:  "net",
+	Usage: "the record is a NetInfo instead of a RoleInfo",
+}
+
+var RoleCmd = &cli.Command{
+	Name:  "role",
+	Usage: "show role and network records",
+	Subcommands: []*cli.Command{
+		roleShowCmd,
+		roleDecodeCmd,
+	},
+}
+
+var roleShowCmd = &cli.Command{
+	Name:      "show",
+	Usage:     "fetch a role record from the gateway, default the registered one",
+	ArgsUsage: "[role id]",
+	Flags: []cli.Flag{
+		netInfoFlag,
+		passwordFileFlag,
+		agentFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		id, err := roleIDArg(cctx)
+		if err != nil {
+			return err
+		}
+
+		client, err := newClient(cctx)
+		if err != nil {
+			return err
+		}
+
+		if cctx.Bool("net") {
+			ni, err := lib.GetNetInfo(cctx.Context, client, id)
+			if err != nil {
+				return err
+			}
+			printNetInfo(ni)
+			return nil
+		}
+
+		ri, err := lib.GetRoleInfo(cctx.Context, client, id)
+		if err != nil {
+			return err
+		}
+		printRoleInfo(ri)
+
+		checkBLSKey(cctx.Context, cctx.String("repo"), ri)
+		return nil
+	},
+}
+
+var roleDecodeCmd = &cli.Command{
+	Name:      "decode",
+	Usage:     "decode a hex or raw protobuf role record",
+	ArgsUsage: "<hex>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "file",
+			Usage: "read the record from file, hex or raw protobuf",
+		},
+		netInfoFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		buf, err := readRecord(cctx)
+		if err != nil {
+			return err
+		}
+
+		if cctx.Bool("net") {
+			ni := new(pb.NetInfo)
+			err = ni.Unmarshal(buf)
+			if err != nil {
+				return xerrors.Errorf("decode net info: %w", err)
+			}
+			printNetInfo(ni)
+			return nil
+		}
+
+		ri := new(pb.RoleInfo)
+		err = ri.Unmarshal(buf)
+		if err != nil {
+			return xerrors.Errorf("decode role info: %w", err)
+		}
+		printRoleInfo(ri)
+
+		checkBLSKey(cctx.Context, cctx.String("repo"), ri)
+		return nil
+	},
+}
+
+// readRecord takes the record from the argument or --file; text is
+// decoded as hex, anything else is taken as raw protobuf.
+func readRecord(cctx *cli.Context) ([]byte, error) {
+	arg := cctx.Args().First()
+	file := cctx.String("file")
+
+	var buf []byte
+	switch {
+	case arg != "" && file != "":
+		return nil, xerrors.New("use either an argument or --file")
+	case file != "":
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		buf = b
+	case arg != "":
+		buf = []byte(arg)
+	default:
+		return nil, xerrors.New("no record, give it as hex or with --file")
+	}
+
+	s := strings.TrimSpace(string(buf))
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		s = "0x" + s
+	}
+
+	dec, err := hexutil.Decode(s)
+	if err == nil {
+		return dec, nil
+	}
+
+	if arg != "" {
+		return nil, xerrors.Errorf("decode hex: %w", err)
+	}
+	return buf, nil
+}
+
+func printRoleInfo(ri *pb.RoleInfo) {
+	fmt.Println("type:     ", ri.GetType())
+	fmt.Println("roleID:   ", ri.GetRoleID())
+	fmt.Println("groupID:  ", ri.GetGroupID())
+
+	printKey("chain:    ", ri.GetChainVerifyKey())
+	printKey("bls:      ", ri.GetBlsVerifyKey())
+
+	if len(ri.GetExtra()) > 0 {
+		fmt.Println("extra:    ", hexutil.Encode(ri.GetExtra()))
+	}
+	if len(ri.GetDesc()) > 0 {
+		fmt.Println("desc:     ", string(ri.GetDesc()))
+	}
+	if ri.GetState() != "" {
+		fmt.Println("state:    ", ri.GetState())
+	}
+}
+
+// printKey prints a verify key as address, with its eth form if it has one.
+func printKey(label string, key []byte) {
+	if len(key) == 0 {
+		fmt.Println(label, "<empty>")
+		return
+	}
+
+	addr, err := address.NewAddress(key)
+	if err != nil {
+		fmt.Println(label, hexutil.Encode(key))
+		return
+	}
+
+	eth, err := addr.Eth()
+	if err == nil {
+		fmt.Println(label, eth, addr)
+		return
+	}
+	fmt.Println(label, addr)
+}
+
+func printNetInfo(ni *pb.NetInfo) {
+	fmt.Println("type:     ", ni.GetType())
+	// netID is a libp2p peer id
+	fmt.Println("netID:    ", b58.Encode(ni.GetNetID()))
+	fmt.Println("addr:     ", hexutil.Encode(ni.GetAddr()))
+}
+
+// checkBLSKey tells whether the bls key of ri is the one in the local
+// wallet. It only reads an existing repo and only warns, the role may be
+// anyone's.
+func checkBLSKey(ctx context.Context, repoDir string, ri *pb.RoleInfo) {
+	exist, err := repo.Exists(repoDir)
+	if err != nil || !exist {
+		return
+	}
+
+	rep, err := repo.NewFSRepo(repoDir)
+	if err != nil {
+		fmt.Println("bls check:", err)
+		return
+	}
+
+	defer func() {
+		_ = rep.Close()
+	}()
+
+	// listing needs no password
+	addrs, err := wallet.New("", rep.KeyStore()).WalletList(ctx)
+	if err != nil {
+		fmt.Println("bls check:", err)
+		return
+	}
+
+	local := 0
+	for _, addr := range addrs {
+		if addr.Kind() != address.KindBLS {
+			continue
+		}
+		local++
+
+		if string(addr.Bytes()) == string(ri.GetBlsVerifyKey()) {
+			fmt.Println("bls check: matches local wallet")
+			return
+		}
+	}
+
+	if local == 0 {
+		fmt.Println("bls check: no bls key in local wallet")
+		return
+	}
+
+	fmt.Printf("bls check: warning, bls key of role %d is not in local wallet\n", ri.GetRoleID())
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/memoio/memo-client/lib/address"
+	"github.com/memoio/memo-client/lib/crypto/signature"
+	"github.com/memoio/memo-client/lib/crypto/signature/secp256k1"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+)
+
+var msgFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "msg",
+		Usage: "message to sign or verify",
+	},
+	&cli.StringFlag{
+		Name:  "file",
+		Usage: "read the message from file",
+	},
+	&cli.BoolFlag{
+		Name:  "eip191",
+		Usage: "use ethereum personal_sign (EIP-191) hashing, secp256k1 only",
+	},
+}
+
+var SignCmd = &cli.Command{
+	Name:  "sign",
+	Usage: "sign a message with a wallet key",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:     "from",
+			Usage:    "eth address or Me address of the signing key",
+			Required: true,
+		},
+		passwordFileFlag,
+		agentFlag,
+	}, msgFlags...),
+	Action: func(cctx *cli.Context) error {
+		addr, err := address.Parse(cctx.String("from"))
+		if err != nil {
+			return err
+		}
+
+		addr, err = uncompressedAddress(addr)
+		if err != nil {
+			return err
+		}
+
+		msg, err := readMsg(cctx)
+		if err != nil {
+			return err
+		}
+
+		personal := cctx.Bool("eip191")
+		if personal {
+			if addr.Len() != 20 && addr.Len() != secp256k1.PublicKeySize {
+				return xerrors.New("eip191 needs a secp256k1 key")
+			}
+			msg = signature.PersonalHash(msg)
+		}
+
+		signer, closer, err := getSigner(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		sig, err := signer.WalletSign(cctx.Context, addr, msg)
+		if err != nil {
+			return err
+		}
+
+		// wallets expect v in {27, 28} for personal_sign
+		if personal {
+			sig[secp256k1.SignatureSize-1] += 27
+		}
+
+		fmt.Println(hexutil.Encode(sig))
+		return nil
+	},
+}
+
+var VerifyCmd = &cli.Command{
+	Name:  "verify",
+	Usage: "verify a message signature",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:     "addr",
+			Usage:    "eth address or Me address of the signer",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:     "sig",
+			Usage:    "hex encoded signature",
+			Required: true,
+		},
+	}, msgFlags...),
+	Action: func(cctx *cli.Context) error {
+		addr, err := address.Parse(cctx.String("addr"))
+		if err != nil {
+			return err
+		}
+
+		sig, err := hexutil.Decode(cctx.String("sig"))
+		if err != nil {
+			return xerrors.Errorf("decode signature: %w", err)
+		}
+
+		msg, err := readMsg(cctx)
+		if err != nil {
+			return err
+		}
+
+		if cctx.Bool("eip191") {
+			msg = signature.PersonalHash(msg)
+			if len(sig) == secp256k1.SignatureSize && sig[secp256k1.SignatureSize-1] >= 27 {
+				sig[secp256k1.SignatureSize-1] -= 27
+			}
+		}
+
+		ok, err := signature.Verify(addr.Bytes(), msg, sig)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return xerrors.New("signature is invalid")
+		}
+
+		fmt.Println("signature is valid")
+		return nil
+	},
+}
+
+// uncompressedAddress turns the address of a compressed secp256k1 key
+// into the uncompressed one its key is stored under in the wallet.
+func uncompressedAddress(addr address.Address) (address.Address, error) {
+	if addr.Len() != secp256k1.PublicKeyCompressedSize {
+		return addr, nil
+	}
+
+	pk := &secp256k1.PublicKey{}
+	err := pk.Deserialize(addr.Bytes())
+	if err != nil {
+		return address.Undef, xerrors.Errorf("%s is no secp256k1 key: %w", addr, err)
+	}
+
+	pub, err := pk.Raw()
+	if err != nil {
+		return address.Undef, err
+	}
+	return address.NewAddress(pub)
+}
+
+func readMsg(cctx *cli.Context) ([]byte, error) {
+	msg := cctx.String("msg")
+	file := cctx.String("file")
+
+	switch {
+	case msg != "" && file != "":
+		return nil, xerrors.New("use either --msg or --file")
+	case file != "":
+		return os.ReadFile(file)
+	case msg != "":
+		return []byte(msg), nil
+	default:
+		return nil, xerrors.New("no message, use --msg or --file")
+	}
+}
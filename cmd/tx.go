@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/memoio/memo-client/lib/chain"
+	"github.com/memoio/memo-client/lib/repo"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+)
+
+var TxCmd = &cli.Command{
+	Name:  "tx",
+	Usage: "build, sign and send transactions and follow them",
+	Subcommands: []*cli.Command{
+		txBuildCmd,
+		txSignCmd,
+		txSendCmd,
+		txListCmd,
+		txUnreserveCmd,
+		txStatusCmd,
+		txWaitCmd,
+		txSpeedupCmd,
+		txCancelCmd,
+	},
+}
+
+var txListCmd = &cli.Command{
+	Name:  "list",
+	Usage: "list sent transactions that are not confirmed yet and nonces reserved by build",
+	Action: func(cctx *cli.Context) error {
+		return withTxManager(cctx, func(m *chain.TxManager) error {
+			ps, err := m.Pending()
+			if err != nil {
+				return err
+			}
+
+			for _, p := range ps {
+				line := fmt.Sprintf("%s %s nonce %d sent %s", p.Hash, p.From, p.Nonce, p.Sent.Format("2006-01-02 15:04:05"))
+				if p.Replaces != (ethcommon.Hash{}) {
+					line += " replaces " + p.Replaces.Hex()
+				}
+				fmt.Println(line)
+			}
+
+			for _, r := range m.Reservations() {
+				fmt.Printf("reserved %s nonce %d built %s expires %s\n", r.From, r.Nonce, r.At.Format("2006-01-02 15:04:05"), m.Expires(r).Format("2006-01-02 15:04:05"))
+			}
+			return nil
+		})
+	},
+}
+
+var txUnreserveCmd = &cli.Command{
+	Name:      "unreserve",
+	Usage:     "free the nonce of a built transaction that will not be sent",
+	ArgsUsage: "<nonce>",
+	Flags: []cli.Flag{
+		fromFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		nonce, err := strconv.ParseUint(cctx.Args().First(), 10, 64)
+		if err != nil {
+			return xerrors.Errorf("invalid nonce %q", cctx.Args().First())
+		}
+
+		from, err := accountAddress(cctx)
+		if err != nil {
+			return err
+		}
+
+		return withTxManager(cctx, func(m *chain.TxManager) error {
+			return m.Unreserve(ethcommon.BytesToAddress(from.Bytes()), nonce)
+		})
+	},
+}
+
+var txStatusCmd = &cli.Command{
+	Name:      "status",
+	Usage:     "show the state of a transaction",
+	ArgsUsage: "<hash>",
+	Action: func(cctx *cli.Context) error {
+		hash, err := txHashArg(cctx)
+		if err != nil {
+			return err
+		}
+
+		return withTxManager(cctx, func(m *chain.TxManager) error {
+			s, err := m.Status(cctx.Context, hash)
+			if err != nil && err != chain.ErrTxUnknown {
+				return err
+			}
+
+			fmt.Println("tx:      ", hash)
+			fmt.Println("state:   ", s)
+			if s.Receipt != nil {
+				printReceipt(s.Receipt)
+				fmt.Println("confirms:", s.Confirmations)
+			}
+			if s.Reason != "" {
+				fmt.Println("reason:  ", s.Reason)
+			}
+			return nil
+		})
+	},
+}
+
+var txWaitCmd = &cli.Command{
+	Name:      "wait",
+	Usage:     "wait until a transaction is confirmed",
+	ArgsUsage: "<hash>",
+	Flags: []cli.Flag{
+		&cli.Uint64Flag{
+			Name:  "confirmations",
+			Usage: "blocks to wait for, including the one with the tx",
+			Value: 1,
+		},
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "give up after this long, 0 waits until the tx is final",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		hash, err := txHashArg(cctx)
+		if err != nil {
+			return err
+		}
+
+		return withTxManager(cctx, func(m *chain.TxManager) error {
+			m.Confirmations = cctx.Uint64("confirmations")
+
+			ctx := cctx.Context
+			if d := cctx.Duration("timeout"); d > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, d)
+				defer cancel()
+			}
+
+			r, err := m.Wait(ctx, hash)
+			if r != nil {
+				printReceipt(r)
+			}
+			return err
+		})
+	},
+}
+
+var txSpeedupCmd = &cli.Command{
+	Name:      "speedup",
+	Usage:     "re-send a pending transaction with a higher fee",
+	ArgsUsage: "<hash>",
+	Flags: []cli.Flag{
+		passwordFileFlag,
+		agentFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		return replaceTx(cctx, false)
+	},
+}
+
+var txCancelCmd = &cli.Command{
+	Name:      "cancel",
+	Usage:     "replace a pending transaction by an empty one",
+	ArgsUsage: "<hash>",
+	Flags: []cli.Flag{
+		passwordFileFlag,
+		agentFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		return replaceTx(cctx, true)
+	},
+}
+
+func replaceTx(cctx *cli.Context, cancel bool) error {
+	hash, err := txHashArg(cctx)
+	if err != nil {
+		return err
+	}
+
+	return withTxManager(cctx, func(m *chain.TxManager) error {
+		p, err := m.Get(hash)
+		if err != nil {
+			return xerrors.Errorf("tx %s is not tracked: %w", hash, err)
+		}
+
+		c, err := getChain(cctx)
+		if err != nil {
+			return err
+		}
+
+		signer, closer, err := getSigner(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		opts, err := c.TransactOpts(cctx.Context, signer, p.From)
+		if err != nil {
+			return err
+		}
+
+		tx, err := m.Replace(cctx.Context, opts, hash, cancel)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("tx:      ", tx.Hash())
+		fmt.Println("replaces:", hash)
+		return nil
+	})
+}
+
+func txHashArg(cctx *cli.Context) (ethcommon.Hash, error) {
+	b, err := hexutil.Decode(cctx.Args().First())
+	if err != nil || len(b) != ethcommon.HashLength {
+		return ethcommon.Hash{}, xerrors.Errorf("invalid tx hash %q", cctx.Args().First())
+	}
+	return ethcommon.BytesToHash(b), nil
+}
+
+// withTxManager runs fn with the tx manager on the repo state.
+func withTxManager(cctx *cli.Context, fn func(m *chain.TxManager) error) error {
+	c, err := getChain(cctx)
+	if err != nil {
+		return err
+	}
+
+	rep, err := repo.NewFSRepo(cctx.String("repo"))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = rep.Close()
+	}()
+
+	return fn(chain.NewTxManager(c, rep.StateStore()))
+}
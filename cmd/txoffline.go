@@ -0,0 +1,397 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/memoio/memo-client/lib/chain"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/xerrors"
+)
+
+var nonceFlag = &cli.Int64Flag{
+	Name:  "nonce",
+	Usage: "nonce of the tx, default the next nonce the repo hands out for the account, reserved until sent, see 'tx unreserve'",
+	Value: -1,
+}
+
+var amountFlag = &cli.StringFlag{
+	Name:     "amount",
+	Usage:    "amount in MEMO",
+	Required: true,
+}
+
+var txBuildCmd = &cli.Command{
+	Name:  "build",
+	Usage: "build an unsigned transaction to sign offline",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "transfer",
+			Usage: "send MEMO to an address",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "to",
+					Usage:    "receiver address",
+					Required: true,
+				},
+				amountFlag,
+				fromFlag,
+				nonceFlag,
+			},
+			Action: func(cctx *cli.Context) error {
+				to, err := ethAddress(cctx.String("to"))
+				if err != nil {
+					return err
+				}
+
+				return buildTx(cctx, "transfer", func(c *chain.Client, opts *bind.TransactOpts, amount *big.Int) (*types.Transaction, error) {
+					return c.Transfer(opts, to, amount)
+				})
+			},
+		},
+		{
+			Name:  "approve",
+			Usage: "allow a contract to spend MEMO of the account",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "to",
+					Usage: "spender address, default the storage contract",
+				},
+				amountFlag,
+				fromFlag,
+				nonceFlag,
+			},
+			Action: func(cctx *cli.Context) error {
+				return buildTx(cctx, "approve", func(c *chain.Client, opts *bind.TransactOpts, amount *big.Int) (*types.Transaction, error) {
+					spender := c.Profile().Store
+					if to := cctx.String("to"); to != "" {
+						var err error
+						spender, err = ethAddress(to)
+						if err != nil {
+							return nil, err
+						}
+					}
+					return c.Approve(opts, spender, amount)
+				})
+			},
+		},
+		{
+			Name:  "deposit",
+			Usage: "deposit MEMO for storage",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "to",
+					Usage: "address whose storage is paid, default the account",
+				},
+				&cli.StringFlag{
+					Name:  "memo",
+					Usage: "note stored with the deposit",
+				},
+				amountFlag,
+				fromFlag,
+				nonceFlag,
+			},
+			Action: func(cctx *cli.Context) error {
+				return buildTx(cctx, "deposit", func(c *chain.Client, opts *bind.TransactOpts, amount *big.Int) (*types.Transaction, error) {
+					to := opts.From
+					if s := cctx.String("to"); s != "" {
+						var err error
+						to, err = ethAddress(s)
+						if err != nil {
+							return nil, err
+						}
+					}
+					return c.StoreDeposit(opts, to, amount, cctx.String("memo"))
+				})
+			},
+		},
+	},
+}
+
+var txSignCmd = &cli.Command{
+	Name:      "sign",
+	Usage:     "sign a built transaction with the local wallet, no node is needed",
+	ArgsUsage: "<file | ->",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"y"},
+			Usage:   "sign without asking, needed when the tx comes from stdin",
+		},
+		passwordFileFlag,
+		agentFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().First() == "-" && !cctx.Bool("yes") {
+			return xerrors.New("stdin carries the tx, so it cannot confirm: check the tx and pass --yes")
+		}
+
+		buf, err := readInput(cctx.Args().First())
+		if err != nil {
+			return err
+		}
+
+		ut, err := chain.ParseUnsignedTx(buf)
+		if err != nil {
+			return err
+		}
+
+		// the file, desc included, may come from anyone: show what is
+		// signed as decoded from the tx itself; stdout is for the result
+		printUnsignedTx(cctx, ut)
+
+		if !cctx.Bool("yes") {
+			sign := false
+			for i := 0; i < 3; i++ {
+				res, err := ask4confirmTo(os.Stderr, "sign")
+				if err == nil {
+					sign = res
+					break
+				}
+			}
+
+			if !sign {
+				fmt.Fprintln(os.Stderr, "cancel sign")
+				return nil
+			}
+		}
+
+		signer, closer, err := getSigner(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		tx, err := chain.SignTx(cctx.Context, signer, ut.From, ut.Tx, ut.ChainID)
+		if err != nil {
+			return err
+		}
+
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(hexutil.Encode(raw))
+		return nil
+	},
+}
+
+var txSendCmd = &cli.Command{
+	Name:      "send",
+	Usage:     "broadcast a signed transaction",
+	ArgsUsage: "<raw tx hex | file | ->",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "via",
+			Usage: "broadcast through the chain node (rpc) or the gateway (gateway), which only relays token approvals",
+			Value: "rpc",
+		},
+		&cli.BoolFlag{
+			Name:  "wait",
+			Usage: "wait for the tx to be mined, only with --via rpc",
+		},
+		passwordFileFlag,
+		agentFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		arg := cctx.Args().First()
+		var raw []byte
+		if strings.HasPrefix(arg, "0x") {
+			raw = []byte(arg)
+		} else {
+			var err error
+			raw, err = readInput(arg)
+			if err != nil {
+				return err
+			}
+		}
+
+		b, err := hexutil.Decode(strings.TrimSpace(string(raw)))
+		if err != nil {
+			return xerrors.Errorf("decode raw tx: %w", err)
+		}
+
+		tx := new(types.Transaction)
+		err = tx.UnmarshalBinary(b)
+		if err != nil {
+			return xerrors.Errorf("decode raw tx: %w", err)
+		}
+
+		switch cctx.String("via") {
+		case "rpc":
+			return withTxManager(cctx, func(m *chain.TxManager) error {
+				err := m.Send(cctx.Context, tx)
+				if err != nil {
+					return err
+				}
+				fmt.Println("tx:      ", tx.Hash())
+
+				if !cctx.Bool("wait") {
+					return nil
+				}
+
+				r, err := m.Wait(cctx.Context, tx.Hash())
+				if r != nil {
+					printReceipt(r)
+				}
+				return err
+			})
+		case "gateway":
+			if cctx.Bool("wait") {
+				return xerrors.New("--wait needs --via rpc")
+			}
+
+			p, err := getProfile(cctx)
+			if err != nil {
+				return err
+			}
+
+			// the gateway relays nothing but token approvals
+			err = chain.CheckApprove(tx, p.Token)
+			if err != nil {
+				return xerrors.Errorf("gateway only relays approve txs: %w", err)
+			}
+
+			from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+			if err != nil {
+				return err
+			}
+
+			client, err := newClient(cctx)
+			if err != nil {
+				return err
+			}
+
+			// the gateway relays the tx for the bucket of the sender
+			err = client.Approve(cctx.Context, hex.EncodeToString(b), from.Hex())
+			if err != nil {
+				return err
+			}
+			fmt.Println("tx:      ", tx.Hash())
+			return nil
+		default:
+			return xerrors.Errorf("unknown --via %q, use rpc or gateway", cctx.String("via"))
+		}
+	},
+}
+
+// buildTx builds the tx of build for --amount from the account and
+// prints it unsigned; the nonce, fees and gas come from the node.
+func buildTx(cctx *cli.Context, desc string, build func(*chain.Client, *bind.TransactOpts, *big.Int) (*types.Transaction, error)) error {
+	amount, err := chain.ParseAmount(cctx.String("amount"))
+	if err != nil {
+		return err
+	}
+
+	from, err := accountAddress(cctx)
+	if err != nil {
+		return err
+	}
+
+	// the nonce is reserved in the repo state, so txs built or sent here
+	// later do not reuse it before this one is sent; 'tx unreserve' frees
+	// it if it never will be, else it expires
+	return withTxManager(cctx, func(m *chain.TxManager) error {
+		c := m.Client()
+		opts, err := c.UnsignedOpts(cctx.Context, ethcommon.BytesToAddress(from.Bytes()))
+		if err != nil {
+			return err
+		}
+
+		if n := cctx.Int64(nonceFlag.Name); n >= 0 {
+			opts.Nonce = big.NewInt(n)
+		} else {
+			n, err := m.ReserveNonce(cctx.Context, opts.From)
+			if err != nil {
+				return err
+			}
+			opts.Nonce = new(big.Int).SetUint64(n)
+		}
+
+		tx, err := build(c, opts, amount)
+		if err != nil {
+			if cctx.Int64(nonceFlag.Name) < 0 {
+				m.ReleaseNonce(opts.From, opts.Nonce.Uint64())
+			}
+			return err
+		}
+
+		buf, err := json.MarshalIndent(chain.NewUnsignedTx(c.ChainID(), opts.From, desc, tx), "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(buf))
+		return nil
+	})
+}
+
+// printUnsignedTx writes what signing ut commits to on stderr.
+func printUnsignedTx(cctx *cli.Context, ut *chain.UnsignedTx) {
+	tx := ut.Tx
+	w := os.Stderr
+
+	fmt.Fprintln(w, "chain:    ", ut.ChainID)
+	fmt.Fprintln(w, "from:     ", ut.From)
+
+	switch {
+	case tx.To() == nil:
+		fmt.Fprintln(w, "to:        none, creates a contract")
+	default:
+		to := tx.To().Hex()
+		if p, err := getProfile(cctx); err == nil {
+			switch *tx.To() {
+			case p.Token:
+				to += " (MEMO token)"
+			case p.Store:
+				to += " (store contract)"
+			}
+		}
+		fmt.Fprintln(w, "to:       ", to)
+	}
+
+	fmt.Fprintln(w, "value:    ", chain.FormatAmount(tx.Value()))
+	fmt.Fprintln(w, "nonce:    ", tx.Nonce())
+	fmt.Fprintln(w, "gas:      ", tx.Gas())
+	if tx.Type() == types.LegacyTxType {
+		fmt.Fprintln(w, "gas price:", tx.GasPrice(), "wei")
+	} else {
+		fmt.Fprintln(w, "max fee:  ", tx.GasFeeCap(), "wei")
+		fmt.Fprintln(w, "tip:      ", tx.GasTipCap(), "wei")
+	}
+	fmt.Fprintln(w, "max cost: ", chain.FormatAmount(tx.Cost()))
+
+	call, err := chain.DecodeCall(tx.Data())
+	switch {
+	case err != nil:
+		fmt.Fprintf(w, "call:      %s, data %#x\n", err, tx.Data())
+	case call != "":
+		fmt.Fprintln(w, "call:     ", call)
+	}
+
+	if ut.Desc != "" {
+		fmt.Fprintln(w, "desc:     ", ut.Desc, "(from the file, unchecked)")
+	}
+}
+
+// readInput reads the file name, or stdin for "-".
+func readInput(name string) ([]byte, error) {
+	switch name {
+	case "":
+		return nil, xerrors.New("no input, give a file or - for stdin")
+	case "-":
+		return io.ReadAll(os.Stdin)
+	default:
+		return os.ReadFile(name)
+	}
+}
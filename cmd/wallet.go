@@ -1,37 +1,44 @@
 package cmd
 
 import (
-	"encoding/hex"
 	"fmt"
-	"log"
-	"math/big"
 	"os"
-	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/memoio/memo-client/lib"
 	"github.com/memoio/memo-client/lib/address"
+	"github.com/memoio/memo-client/lib/chain"
+	"github.com/memoio/memo-client/lib/crypto/signature"
 	"github.com/memoio/memo-client/lib/repo"
+	ltypes "github.com/memoio/memo-client/lib/types"
+	"github.com/memoio/memo-client/lib/types/store"
 	"github.com/memoio/memo-client/wallet"
 	"github.com/urfave/cli/v2"
-	"golang.org/x/crypto/sha3"
+	"golang.org/x/xerrors"
 )
 
 var WalletCmd = &cli.Command{
 	Name: "wallet",
 	Subcommands: []*cli.Command{
 		WalletListCmd,
-		// WalletApproveCmd,
-		// WalletDeposit,
+		WalletNewCmd,
+		WalletSendCmd,
+		WalletBLSMigrateCmd,
+		WalletApproveCmd,
+		WalletDeposit,
 	},
 }
 
 var WalletListCmd = &cli.Command{
 	Name:  "list",
 	Usage: "list wallet address",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "all",
+			Usage: "also list bls and ed25519 keys as Me addresses",
+		},
+	},
 	Action: func(ctx *cli.Context) error {
 		repoDir := ctx.String("repo")
 
@@ -44,18 +51,28 @@ var WalletListCmd = &cli.Command{
 			_ = rep.Close()
 		}()
 
-		pw := ctx.String("passwd")
-
-		w := wallet.New(pw, rep.KeyStore())
+		// listing needs no password
+		w := wallet.New("", rep.KeyStore())
 		addrs, err := w.WalletList(ctx.Context)
 		if err != nil {
 			return err
 		}
 
+		all := ctx.Bool("all")
 		for _, as := range addrs {
-			if as.Len() == 20 {
-				toAddress := ethcommon.BytesToAddress(as.Bytes())
+			switch as.Len() {
+			case address.EthAddressLength:
+				toAddress, err := as.Eth()
+				if err != nil {
+					return err
+				}
 				fmt.Println(toAddress)
+			case 65:
+				// secp256k1 keys are listed by their eth address
+			default:
+				if all {
+					fmt.Println(as)
+				}
 			}
 		}
 
@@ -63,239 +80,321 @@ var WalletListCmd = &cli.Command{
 	},
 }
 
-var WalletApproveCmd = &cli.Command{
-	Name:  "approve",
-	Usage: "pay fee",
+var WalletNewCmd = &cli.Command{
+	Name:  "new",
+	Usage: "generate a new key in the wallet",
 	Flags: []cli.Flag{
 		&cli.StringFlag{
-			Name:  "taddr",
-			Usage: "approve amount to addr",
+			Name:  "type",
+			Usage: "key type: secp256k1, bls or ed25519",
+			Value: "secp256k1",
 		},
+		passwordFileFlag,
 	},
 	Action: func(cctx *cli.Context) error {
-		taddr := cctx.String("taddr")
-
-		buf, err := os.ReadFile("address")
+		typ, err := ltypes.ParseKeyType(cctx.String("type"))
 		if err != nil {
 			return err
 		}
 
-		bucket := string(buf)
-
-		repoDir := cctx.String("repo")
-
-		rep, err := repo.NewFSRepo(repoDir)
+		rep, err := repo.NewFSRepo(cctx.String("repo"))
 		if err != nil {
-			return nil
+			return err
 		}
 
 		defer func() {
 			_ = rep.Close()
 		}()
 
-		pw := cctx.String("passwd")
-
-		w := wallet.New(pw, rep.KeyStore())
+		pw, err := getPassword(cctx, false)
+		if err != nil {
+			return err
+		}
 
-		maddr := ethcommon.HexToAddress(bucket)
+		privkey, err := signature.GenerateKey(typ)
 		if err != nil {
-			return nil
+			return err
 		}
 
-		srcaddr, err := address.NewAddress(maddr.Bytes())
+		sk, err := privkey.Raw()
 		if err != nil {
 			return err
 		}
 
-		// get sk
-		sks, err := w.WalletExport(cctx.Context, srcaddr, "")
+		w := wallet.New(pw, rep.KeyStore())
+		addr, err := w.WalletImport(cctx.Context, &ltypes.KeyInfo{
+			Type:      typ,
+			SecretKey: sk,
+		})
 		if err != nil {
 			return err
 		}
 
-		sk := hex.EncodeToString(sks.SecretKey)
+		if typ == ltypes.Secp256k1 {
+			eaddr, err := addr.Eth()
+			if err != nil {
+				return err
+			}
+			fmt.Println(eaddr)
+			return nil
+		}
+
+		fmt.Println(addr)
+		return nil
+	},
+}
+
+var WalletBLSMigrateCmd = &cli.Command{
+	Name:  "bls-migrate",
+	Usage: "derive the EIP-2333 bls key from the account key and add it to the wallet",
+	Flags: []cli.Flag{
+		passwordFileFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		buf, err := os.ReadFile("address")
+		if err != nil {
+			return err
+		}
 
-		client, err := lib.New()
+		srcaddr, err := address.NewFromEth(string(buf))
 		if err != nil {
 			return err
 		}
 
-		tokenaddr, err := client.GetTokenAddress(cctx.Context)
+		rep, err := repo.NewFSRepo(cctx.String("repo"))
 		if err != nil {
 			return err
 		}
-		log.Println(tokenaddr)
 
-		tokenaddress := ethcommon.HexToAddress(tokenaddr)
-		toaddress := ethcommon.HexToAddress(taddr)
+		defer func() {
+			_ = rep.Close()
+		}()
 
-		value := big.NewInt(150503225806451)
-		tshash, err := lib.Approve(cctx.Context, sk, tokenaddress, toaddress, value)
+		pw, err := getPassword(cctx, false)
+		if err != nil {
+			return err
+		}
+
+		w := wallet.New(pw, rep.KeyStore())
+		ki, err := w.WalletExport(cctx.Context, srcaddr, pw)
 		if err != nil {
 			return err
 		}
 
-		tsbyte, err := tshash.MarshalBinary()
+		if ki.Type != ltypes.Secp256k1 {
+			return xerrors.Errorf("key of %s is not secp256k1", buf)
+		}
+
+		blsKey, err := deriveBLSKey(ki.SecretKey, blsDeriveEIP2333)
 		if err != nil {
 			return err
 		}
-		ts := hex.EncodeToString(tsbyte)
-		log.Println(ts)
 
-		err = client.Approve(cctx.Context, ts, bucket)
+		blsAddr, err := w.WalletImport(cctx.Context, blsKey)
 		if err != nil {
 			return err
 		}
 
+		fmt.Println(blsAddr)
+
+		// the gateway cannot change the key of a role, only new roles take it
+		id, err := loadRoleID(rep.StateStore())
+		switch err {
+		case nil:
+			fmt.Printf("role %d stays registered with its previous bls key, keep that key in the wallet\n", id)
+		case store.ErrNotFound:
+			fmt.Printf("register with the new key: memo-client register --group <group id> --bls %s\n", blsAddr)
+		default:
+			return err
+		}
 		return nil
 	},
 }
 
-// var WalletSendCmd = &cli.Command{
-// 	Name:      "send",
-// 	Usage:     "send memo to another address",
-// 	UsageText: "send [destaddress][value]",
-// 	Action: func(ctx *cli.Context) error {
-// 		args := ctx.Args()
-// 		destaddr := args.Get(0)
-// 		log.Println(destaddr)
-// 		if destaddr == "" {
-// 			return xerrors.New("addr is nil ")
-// 		}
-
-// 		value := args.Get(1)
-// 		if value == "" {
-// 			return xerrors.New("value is nil")
-// 		}
-// 		// ivalue, err := strconv.ParseInt(value, 10, 64)
-// 		// if err != nil {
-// 		// 	return err
-// 		// }
-
-// 		repoDir := ctx.String("repo")
-
-// 		rep, err := repo.NewFSRepo(repoDir)
-// 		if err != nil {
-// 			return nil
-// 		}
-
-// 		defer func() {
-// 			_ = rep.Close()
-// 		}()
-
-// 		pw := ctx.String("passwd")
-
-// 		buf, err := os.ReadFile("address")
-// 		if err != nil {
-// 			return err
-// 		}
-
-// 		maddr := ethcommon.HexToAddress(string(buf))
-// 		if err != nil {
-// 			return nil
-// 		}
-
-// 		srcaddr, err := address.NewAddress(maddr.Bytes())
-// 		if err != nil {
-// 			return err
-// 		}
-
-// 		sk, err := wallet.GetSk(ctx.Context, repoDir, pw, srcaddr)
-// 		if err != nil {
-// 			return err
-// 		}
-
-// 		hash, err := lib.Signmsg(ctx.Context, sk, value, destaddr, "")
-// 		if err != nil {
-// 			return err
-// 		}
-// 		log.Println(hash)
-// 		return nil
-// 	},
-// }
+var WalletApproveCmd = &cli.Command{
+	Name:  "approve",
+	Usage: "allow a contract to spend MEMO of the account",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "amount",
+			Usage:    "allowance in MEMO",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "to",
+			Usage: "spender address, default the storage contract",
+		},
+		fromFlag,
+		passwordFileFlag,
+		agentFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		amount, err := chain.ParseAmount(cctx.String("amount"))
+		if err != nil {
+			return err
+		}
 
-var WalletDeposit = &cli.Command{
-	Usage: "deposit",
-	Name:  "deposit",
-	Action: func(ctx *cli.Context) error {
-		// buf, err := os.ReadFile("address")
-		// if err != nil {
-		// 	return err
-		// }
+		a, err := openAccount(cctx)
+		if err != nil {
+			return err
+		}
+		defer a.Close()
 
-		maddr := ethcommon.HexToAddress("0xecF059784977F181ECfc38C827ee818330bC76aD")
+		spender := a.c.Profile().Store
+		if to := cctx.String("to"); to != "" {
+			spender, err = ethAddress(to)
+			if err != nil {
+				return err
+			}
+		}
 
-		client, err := ethclient.Dial("https://chain.metamemo.one:8501")
+		allowance, err := a.c.Allowance(cctx.Context, a.opts.From, spender)
 		if err != nil {
 			return err
 		}
-		defer client.Close()
-		storeaddr := ethcommon.HexToAddress("0x31e7829Ea2054fDF4BCB921eDD3a98a825242267")
-		toaddr := ethcommon.HexToAddress("0xCcf7b7F747100f3393a75DDf6864589f76F4eA25")
-		nonce, err := client.PendingNonceAt(ctx.Context, storeaddr)
+
+		if allowance.Cmp(amount) == 0 {
+			fmt.Printf("allowance of %s is already %s MEMO\n", spender, chain.FormatAmount(allowance))
+			return nil
+		}
+		fmt.Printf("allowance of %s: %s -> %s MEMO\n", spender, chain.FormatAmount(allowance), chain.FormatAmount(amount))
+
+		return a.transact(cctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return a.c.Approve(opts, spender, amount)
+		})
+	},
+}
+
+var WalletSendCmd = &cli.Command{
+	Name:      "send",
+	Usage:     "send MEMO to another address",
+	ArgsUsage: "<to> <amount>",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"y"},
+			Usage:   "send without asking",
+		},
+		fromFlag,
+		passwordFileFlag,
+		agentFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.Args().Len() != 2 {
+			return xerrors.New("need the receiver and the amount")
+		}
+
+		to, err := ethAddress(cctx.Args().Get(0))
 		if err != nil {
 			return err
 		}
-		log.Println("nonce: ", nonce)
+		if to == (ethcommon.Address{}) {
+			return xerrors.New("refuse to send to the zero address")
+		}
 
-		chainID, err := client.NetworkID(ctx.Context)
+		amount, err := chain.ParseAmount(cctx.Args().Get(1))
 		if err != nil {
-			log.Println(err)
 			return err
 		}
-		log.Println("chainID: ", chainID)
-
-		depositFnSignature := []byte("storeDeposit(address,uint256,string)")
-		hash := sha3.NewLegacyKeccak256()
-		hash.Write(depositFnSignature)
-		methodID := hash.Sum(nil)[:4]
-
-		amount := big.NewInt(1000000000000000000)
-		paddedAddress := ethcommon.LeftPadBytes(maddr.Bytes(), 32)
-		paddedAmount := ethcommon.LeftPadBytes(amount.Bytes(), 32)
-		paddedHashLen := ethcommon.LeftPadBytes(big.NewInt(int64(len(chainID.Bytes()))).Bytes(), 32)
-		paddedHashOffset := ethcommon.LeftPadBytes(big.NewInt(32*3).Bytes(), 32)
-		paddedChainID := ethcommon.RightPadBytes(chainID.Bytes(), 32)
-
-		var data []byte
-		data = append(data, methodID...)
-		data = append(data, paddedAddress...)
-		data = append(data, paddedAmount...)
-		data = append(data, paddedHashOffset...)
-		data = append(data, paddedHashLen...)
-		data = append(data, paddedChainID...)
-
-		gasLimit := uint64(300000)
-		gasPrice := big.NewInt(1000)
-		tx := types.NewTransaction(nonce, toaddr, big.NewInt(0), gasLimit, gasPrice, data)
-
-		privateKey, err := crypto.HexToECDSA("8a87053d296a0f0b4600173773c8081b12917cef7419b2675943b0aa99429b62")
+		if amount.Sign() <= 0 {
+			return xerrors.New("amount must be positive")
+		}
+
+		a, err := openAccount(cctx)
 		if err != nil {
-			log.Println("get privateKey error: ", err)
 			return err
 		}
-		log.Println(privateKey)
+		defer a.Close()
 
-		signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), privateKey)
+		if to == a.c.Profile().Token {
+			return xerrors.New("refuse to send to the token contract, the MEMO would be lost")
+		}
+
+		balance, err := a.c.BalanceOf(cctx.Context, a.opts.From)
 		if err != nil {
-			log.Println("signedTx error: ", err)
 			return err
 		}
+		if balance.Cmp(amount) < 0 {
+			return xerrors.Errorf("balance of %s is %s MEMO, less than %s", a.opts.From, chain.FormatAmount(balance), chain.FormatAmount(amount))
+		}
+
+		fmt.Printf("send %s MEMO from %s to %s, balance %s MEMO\n", chain.FormatAmount(amount), a.opts.From, to, chain.FormatAmount(balance))
 
-		err = client.SendTransaction(ctx.Context, signedTx)
+		if !cctx.Bool("yes") {
+			send := false
+			for i := 0; i < 3; i++ {
+				res, err := ask4confirm("send")
+				if err == nil {
+					send = res
+					break
+				}
+			}
+
+			if !send {
+				fmt.Println("cancel send")
+				return nil
+			}
+		}
+
+		return a.transact(cctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return a.c.Transfer(opts, to, amount)
+		})
+	},
+}
+
+var WalletDeposit = &cli.Command{
+	Name:  "deposit",
+	Usage: "deposit MEMO for storage",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "amount",
+			Usage:    "deposit in MEMO",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  "to",
+			Usage: "address whose storage is paid, default the account",
+		},
+		&cli.StringFlag{
+			Name:  "memo",
+			Usage: "note stored with the deposit",
+		},
+		fromFlag,
+		passwordFileFlag,
+		agentFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		amount, err := chain.ParseAmount(cctx.String("amount"))
+		if err != nil {
+			return err
+		}
+
+		a, err := openAccount(cctx)
 		if err != nil {
-			log.Println(err)
 			return err
 		}
+		defer a.Close()
 
-		log.Println("waiting tx complete...")
-		time.Sleep(30 * time.Second)
+		to := a.opts.From
+		if s := cctx.String("to"); s != "" {
+			to, err = ethAddress(s)
+			if err != nil {
+				return err
+			}
+		}
 
-		receipt, err := client.TransactionReceipt(ctx.Context, signedTx.Hash())
+		// the store contract pulls the deposit from the account
+		allowance, err := a.c.Allowance(cctx.Context, a.opts.From, a.c.Profile().Store)
 		if err != nil {
 			return err
 		}
-		log.Println(receipt.Status)
-		return nil
+
+		if allowance.Cmp(amount) < 0 {
+			return xerrors.Errorf("allowance of the storage contract is %s MEMO, approve at least %s first", chain.FormatAmount(allowance), chain.FormatAmount(amount))
+		}
+
+		return a.transact(cctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			return a.c.StoreDeposit(opts, to, amount, cctx.String("memo"))
+		})
 	},
 }
@@ -12,74 +12,171 @@ var UndefAddressString = "<empty>"
 
 const ChecksumHashLength = 4
 
-const MaxAddressStringLength = AddrPrefixLen + 94
-
-type  Address struct{ str string }
+// kind byte + 65 byte secp256k1 key + checksum in base58
+const MaxAddressStringLength = AddrPrefixLen + 96
+
+// Address is a key payload with its kind; the kind is inferred from the
+// payload length unless given explicitly.
+type Address struct {
+	str  string
+	kind Kind
+}
 
 var Undef = Address{}
 
+// AddrPrefix is the prefix of mainnet and of legacy addresses.
 const AddrPrefix = "Me"
 const AddrPrefixLen = 2
 
 func NewAddress(payload []byte) (Address, error) {
-	return newAddress(payload)
+	return newAddress(KindOf(payload), payload)
+}
+
+// NewAddressWithKind checks that payload fits kind.
+func NewAddressWithKind(kind Kind, payload []byte) (Address, error) {
+	if !kind.fits(len(payload)) {
+		return Undef, xerrors.Errorf("invalid %s address length %d", kind, len(payload))
+	}
+	return newAddress(kind, payload)
 }
 
-func newAddress(payload []byte) (Address, error) {
+func newAddress(kind Kind, payload []byte) (Address, error) {
 	buf := make([]byte, len(payload))
 	copy(buf[:], payload)
-	return Address{string(buf)}, nil
+	return Address{str: string(buf), kind: kind}, nil
 }
 
+// NewFromString decodes an address of the current network, or a legacy
+// one without kind and network.
 func NewFromString(s string) (Address, error) {
-	return decode(s)
+	return decode(s, CurrentNetwork)
 }
 
-func decode(a string) (Address, error) {
-	if len(a) == 0 {
-		return Undef, xerrors.New("invalid address length")
+// NewFromLegacyString decodes an address without kind and network, as
+// used for keystore names.
+func NewFromLegacyString(s string) (Address, error) {
+	payload, err := decodeLegacy(s)
+	if err != nil {
+		return Undef, err
 	}
-	if a == UndefAddressString {
-		return Undef, xerrors.New("invalid address length")
+	return NewAddress(payload)
+}
+
+func decode(a string, net Network) (Address, error) {
+	payloadcksm, err := decodeRaw(a)
+	if err != nil {
+		return Undef, err
 	}
-	if len(a) > MaxAddressStringLength || len(a) < 3 {
-		return Undef, xerrors.New("invalid address length")
+
+	prefix := a[0:AddrPrefixLen]
+	if prefix != net.Prefix() {
+		n, ok := networkOf(prefix)
+		if !ok {
+			return Undef, xerrors.New("unknown address type")
+		}
+		return Undef, xerrors.Errorf("address of %s, expect %s", n, net)
 	}
 
-	if string(a[0:AddrPrefixLen]) != AddrPrefix {
-		return Undef, xerrors.New("unknown address type")
+	kind, payload, ok := splitTyped(prefix, payloadcksm)
+	if ok {
+		return newAddress(kind, payload)
 	}
 
-	raw := a[AddrPrefixLen:]
+	// legacy addresses only carry the mainnet prefix
+	if prefix != AddrPrefix {
+		return Undef, xerrors.New("invalid address checksum")
+	}
 
-	payloadcksm, err := b58.Decode(raw)
+	payload, err = splitLegacy(payloadcksm)
 	if err != nil {
 		return Undef, err
 	}
+	return NewAddress(payload)
+}
+
+func decodeRaw(a string) ([]byte, error) {
+	if len(a) == 0 {
+		return nil, xerrors.New("invalid address length")
+	}
+	if a == UndefAddressString {
+		return nil, xerrors.New("invalid address length")
+	}
+	if len(a) > MaxAddressStringLength || len(a) < 3 {
+		return nil, xerrors.New("invalid address length")
+	}
+
+	return b58.Decode(a[AddrPrefixLen:])
+}
+
+func decodeLegacy(a string) ([]byte, error) {
+	payloadcksm, err := decodeRaw(a)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(a[0:AddrPrefixLen]) != AddrPrefix {
+		return nil, xerrors.New("unknown address type")
+	}
+
+	return splitLegacy(payloadcksm)
+}
 
+func splitLegacy(payloadcksm []byte) ([]byte, error) {
 	if len(payloadcksm)-ChecksumHashLength < 0 {
-		return Undef, xerrors.New("invalid address checksum")
+		return nil, xerrors.New("invalid address checksum")
 	}
 
 	payload := payloadcksm[:len(payloadcksm)-ChecksumHashLength]
 	cksm := payloadcksm[len(payloadcksm)-ChecksumHashLength:]
 
 	if !ValidateChecksum(payload, cksm) {
-		return Undef, xerrors.New("invalid address checksum")
+		return nil, xerrors.New("invalid address checksum")
 	}
 
-	return newAddress(payload)
+	return payload, nil
 }
 
-func encode(addr Address) (string, error) {
+// splitTyped parses kind || payload || checksum(prefix || kind || payload).
+func splitTyped(prefix string, payloadcksm []byte) (Kind, []byte, bool) {
+	if len(payloadcksm) < 1+ChecksumHashLength {
+		return KindUnknown, nil, false
+	}
+
+	body := payloadcksm[:len(payloadcksm)-ChecksumHashLength]
+	cksm := payloadcksm[len(payloadcksm)-ChecksumHashLength:]
+
+	kind := Kind(body[0])
+	payload := body[1:]
+	if kind == KindUnknown || !kind.fits(len(payload)) {
+		return KindUnknown, nil, false
+	}
+
+	if !ValidateChecksum(append([]byte(prefix), body...), cksm) {
+		return KindUnknown, nil, false
+	}
+
+	return kind, payload, true
+}
+
+func encode(addr Address, net Network) (string, error) {
 	if addr == Undef {
 		return UndefAddressString, nil
 	}
 
-	cksm := Checksum(addr.Bytes())
-	strAddr := AddrPrefix + b58.Encode(append(addr.Bytes(), cksm[:]...))
+	// no kind to encode, fall back to the legacy form
+	if addr.kind == KindUnknown {
+		return encodeLegacy(addr), nil
+	}
+
+	prefix := net.Prefix()
+	body := append([]byte{byte(addr.kind)}, addr.Bytes()...)
+	cksm := Checksum(append([]byte(prefix), body...))
+	return prefix + b58.Encode(append(body, cksm...)), nil
+}
 
-	return strAddr, nil
+func encodeLegacy(addr Address) string {
+	cksm := Checksum(addr.Bytes())
+	return AddrPrefix + b58.Encode(append(addr.Bytes(), cksm[:]...))
 }
 
 func Checksum(ingest []byte) []byte {
@@ -102,10 +199,24 @@ func (a Address) Bytes() []byte {
 	return []byte(a.str)
 }
 
+func (a Address) Kind() Kind {
+	return a.kind
+}
+
+// String encodes the address with its kind for the current network.
 func (a Address) String() string {
-	str, err := encode(a)
+	str, err := encode(a, CurrentNetwork)
 	if err != nil {
 		panic(err)
 	}
 	return str
 }
+
+// LegacyString encodes the payload only, independent of kind and network;
+// keystore entries are named by it.
+func (a Address) LegacyString() string {
+	if a == Undef {
+		return UndefAddressString
+	}
+	return encodeLegacy(a)
+}
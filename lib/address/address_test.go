@@ -0,0 +1,241 @@
+package address
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// the public key of secret key 1, whose eth address is well known
+const (
+	g1Uncompressed = "0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"
+	g1Compressed   = "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+	g1Eth          = "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func withNetwork(t *testing.T, n Network) {
+	t.Helper()
+	old := CurrentNetwork
+	CurrentNetwork = n
+	t.Cleanup(func() { CurrentNetwork = old })
+}
+
+func payload(n int) []byte {
+	return bytes.Repeat([]byte{7}, n)
+}
+
+func TestKindNetworkRoundTrip(t *testing.T) {
+	cases := []struct {
+		payload []byte
+		kind    Kind
+	}{
+		{payload(EthAddressLength), KindEth},
+		{mustHex(t, g1Uncompressed), KindSecp256k1},
+		{mustHex(t, g1Compressed), KindSecp256k1},
+		{payload(48), KindBLS},
+		{payload(32), KindEd25519},
+	}
+
+	for _, n := range []Network{Mainnet, Testnet, Devnet} {
+		for _, c := range cases {
+			withNetwork(t, n)
+
+			addr, err := NewAddress(c.payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if addr.Kind() != c.kind {
+				t.Fatalf("%d bytes: kind %s, want %s", len(c.payload), addr.Kind(), c.kind)
+			}
+
+			s := addr.String()
+			if !strings.HasPrefix(s, n.Prefix()) {
+				t.Fatalf("%s address %s has no prefix %s", n, s, n.Prefix())
+			}
+
+			got, err := NewFromString(s)
+			if err != nil {
+				t.Fatalf("%s %s: %s", n, c.kind, err)
+			}
+			if got != addr {
+				t.Fatalf("%s %s: decoded %x, kind %s", n, c.kind, got.Bytes(), got.Kind())
+			}
+
+			// an address of one network is rejected by the others
+			for _, other := range []Network{Mainnet, Testnet, Devnet} {
+				if other == n {
+					continue
+				}
+				_, err := decode(s, other)
+				if err == nil {
+					t.Fatalf("%s address accepted on %s", n, other)
+				}
+			}
+		}
+	}
+}
+
+func TestNewAddressWithKind(t *testing.T) {
+	_, err := NewAddressWithKind(KindBLS, payload(32))
+	if err == nil {
+		t.Fatal("32 bytes accepted as bls")
+	}
+
+	// no kind to encode, the string is the legacy one
+	addr, err := NewAddressWithKind(KindUnknown, payload(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.String() != addr.LegacyString() {
+		t.Fatalf("unknown kind encoded as %s, not legacy %s", addr, addr.LegacyString())
+	}
+
+	got, err := NewFromString(addr.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Bytes(), addr.Bytes()) {
+		t.Fatal("unknown kind address does not round trip")
+	}
+}
+
+func TestLegacyNames(t *testing.T) {
+	cases := []struct {
+		payload []byte
+		legacy  string
+	}{
+		// keystore names written before addresses carried kind and network,
+		// as the previous encoding printed them
+		{mustHex(t, g1Uncompressed), "Me3atxpcZDVBv63NwYXQbymMaPpD5f7nqAhRNR7kx1rPPHA1pJ6XzsL2eZ3H4KtCSM1DT5Y4mAY2Ave2BfpFwVMrqgbEPnhV"},
+		{mustHex(t, strings.ToLower(g1Eth[2:])), "MeCXCGhe71gRYUUpa3zmiEJpQe4bDdzREuF"},
+	}
+
+	for _, c := range cases {
+		addr, err := NewAddress(c.payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if addr.LegacyString() != c.legacy {
+			t.Fatalf("legacy name %s, want %s", addr.LegacyString(), c.legacy)
+		}
+
+		got, err := NewFromLegacyString(c.legacy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != addr {
+			t.Fatalf("legacy %s decoded to %x", c.legacy, got.Bytes())
+		}
+
+		// old strings stay valid input on mainnet only
+		withNetwork(t, Mainnet)
+		got, err = NewFromString(c.legacy)
+		if err != nil || got != addr {
+			t.Fatalf("legacy %s on mainnet: %v", c.legacy, err)
+		}
+
+		withNetwork(t, Testnet)
+		_, err = NewFromString(c.legacy)
+		if err == nil {
+			t.Fatalf("legacy %s accepted on testnet", c.legacy)
+		}
+	}
+
+	_, err := NewFromLegacyString("Mt" + cases[0].legacy[AddrPrefixLen:])
+	if err == nil {
+		t.Fatal("legacy name with a testnet prefix accepted")
+	}
+}
+
+func TestEth(t *testing.T) {
+	for _, pk := range []string{g1Uncompressed, g1Compressed, strings.ToLower(g1Eth[2:])} {
+		addr, err := NewAddress(mustHex(t, pk))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		eth, err := addr.Eth()
+		if err != nil {
+			t.Fatalf("%s: %s", pk, err)
+		}
+		if eth != g1Eth {
+			t.Fatalf("%s: eth %s, want %s", pk, eth, g1Eth)
+		}
+	}
+
+	addr, err := NewAddress(payload(48))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = addr.Eth()
+	if err == nil {
+		t.Fatal("bls key has an eth address")
+	}
+}
+
+func TestEthChecksum(t *testing.T) {
+	bad := strings.Replace(g1Eth, "E", "e", 1)
+	cases := []struct {
+		s       string
+		checked bool
+		ok      bool
+	}{
+		{g1Eth, true, true},
+		{strings.ToLower(g1Eth), false, false},
+		{"0x" + strings.ToUpper(g1Eth[2:]), false, false},
+		{bad, true, false},
+	}
+
+	for _, c := range cases {
+		checked, ok := EthChecksumState(c.s)
+		if checked != c.checked || ok != c.ok {
+			t.Fatalf("%s: checked %v ok %v", c.s, checked, ok)
+		}
+
+		_, err := NewFromEth(c.s)
+		if (err == nil) != (!c.checked || c.ok) {
+			t.Fatalf("%s: parse error %v", c.s, err)
+		}
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	addr, err := NewAddress(mustHex(t, g1Compressed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := json.Marshal(struct{ A Address }{addr})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct{ A Address }
+	err = json.Unmarshal(buf, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.A != addr {
+		t.Fatalf("json round trip gave %x", out.A.Bytes())
+	}
+
+	err = json.Unmarshal([]byte(`{"A":"`+g1Eth+`"}`), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.A.Kind() != KindEth {
+		t.Fatalf("eth json decoded as %s", out.A.Kind())
+	}
+}
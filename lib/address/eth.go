@@ -0,0 +1,141 @@
+package address
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/memoio/memo-client/lib/utils"
+	"golang.org/x/crypto/sha3"
+	"golang.org/x/xerrors"
+)
+
+const EthAddressLength = 20
+
+var ErrNoEthAddress = xerrors.New("address has no eth form")
+
+// NewFromPubkey returns the Me address of a public key.
+func NewFromPubkey(pubkey []byte) (Address, error) {
+	if len(pubkey) == 0 {
+		return Undef, xerrors.New("empty public key")
+	}
+	return NewAddress(pubkey)
+}
+
+// NewFromEth parses a hex eth address; a mixed case one must carry a
+// valid EIP-55 checksum.
+func NewFromEth(s string) (Address, error) {
+	raw := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(raw) != 2*EthAddressLength {
+		return Undef, xerrors.Errorf("invalid eth address length: %s", s)
+	}
+
+	b, err := hex.DecodeString(raw)
+	if err != nil {
+		return Undef, err
+	}
+
+	if checked, ok := checkEthChecksum(raw, b); checked && !ok {
+		return Undef, xerrors.Errorf("invalid eth address checksum: %s", s)
+	}
+
+	return NewAddress(b)
+}
+
+// EthChecksumState tells whether the hex eth address s carries an EIP-55
+// checksum, only mixed case ones do, and whether it is valid.
+func EthChecksumState(s string) (checked, ok bool) {
+	raw := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	b, err := hex.DecodeString(raw)
+	if err != nil || len(b) != EthAddressLength {
+		return false, false
+	}
+	return checkEthChecksum(raw, b)
+}
+
+func checkEthChecksum(raw string, b []byte) (checked, ok bool) {
+	if raw == strings.ToLower(raw) || raw == strings.ToUpper(raw) {
+		return false, false
+	}
+	return true, EthChecksum(b) == "0x"+raw
+}
+
+// NewFromHex decodes the raw payload of an address from hex.
+func NewFromHex(s string) (Address, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return Undef, err
+	}
+
+	if len(b) == 0 {
+		return Undef, xerrors.New("invalid address length")
+	}
+	return NewAddress(b)
+}
+
+// Parse accepts a Me address or a hex eth address.
+func Parse(s string) (Address, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return NewFromEth(s)
+	}
+	return NewFromString(s)
+}
+
+// EthChecksum returns the EIP-55 mixed case hex form of a 20 byte address.
+func EthChecksum(addr []byte) string {
+	lower := hex.EncodeToString(addr)
+
+	d := sha3.NewLegacyKeccak256()
+	d.Write([]byte(lower))
+	hash := d.Sum(nil)
+
+	res := []byte(lower)
+	for i, c := range res {
+		if c < 'a' {
+			continue
+		}
+
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if nibble&0xf >= 8 {
+			res[i] = c - 'a' + 'A'
+		}
+	}
+
+	return "0x" + string(res)
+}
+
+// Hex returns the raw payload in hex.
+func (a Address) Hex() string {
+	return "0x" + hex.EncodeToString(a.Bytes())
+}
+
+// EthBytes returns the 20 byte eth address of an eth address or of a
+// secp256k1 public key, compressed or not.
+func (a Address) EthBytes() ([]byte, error) {
+	switch a.Len() {
+	case EthAddressLength:
+		return a.Bytes(), nil
+	case 33:
+		pk, err := secp256k1.ParsePubKey(a.Bytes())
+		if err != nil {
+			return nil, xerrors.Errorf("%w: %s", ErrNoEthAddress, err)
+		}
+		return utils.ToEthAddress(pk.SerializeUncompressed()), nil
+	case 65:
+		return utils.ToEthAddress(a.Bytes()), nil
+	default:
+		return nil, ErrNoEthAddress
+	}
+}
+
+// Eth returns the EIP-55 eth address, see EthBytes.
+func (a Address) Eth() (string, error) {
+	b, err := a.EthBytes()
+	if err != nil {
+		return "", err
+	}
+	return EthChecksum(b), nil
+}
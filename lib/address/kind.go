@@ -0,0 +1,70 @@
+package address
+
+import (
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Kind tells which key an address payload belongs to.
+type Kind byte
+
+const (
+	KindUnknown Kind = iota
+	KindSecp256k1
+	KindEth
+	KindBLS
+	KindEd25519
+)
+
+var kindNames = map[Kind]string{
+	KindUnknown:   "unknown",
+	KindSecp256k1: "secp256k1",
+	KindEth:       "eth",
+	KindBLS:       "bls",
+	KindEd25519:   "ed25519",
+}
+
+func (k Kind) String() string {
+	if s, ok := kindNames[k]; ok {
+		return s
+	}
+	return kindNames[KindUnknown]
+}
+
+// ParseKind is the inverse of Kind.String.
+func ParseKind(s string) (Kind, error) {
+	for k, name := range kindNames {
+		if k != KindUnknown && strings.EqualFold(s, name) {
+			return k, nil
+		}
+	}
+	return KindUnknown, xerrors.Errorf("unknown address kind %q", s)
+}
+
+// KindOf infers the kind from the payload length.
+func KindOf(payload []byte) Kind {
+	switch len(payload) {
+	case EthAddressLength:
+		return KindEth
+	case 32:
+		return KindEd25519
+	case 33, 65:
+		return KindSecp256k1
+	case 48:
+		return KindBLS
+	default:
+		return KindUnknown
+	}
+}
+
+func (k Kind) fits(n int) bool {
+	switch k {
+	case KindUnknown:
+		return n > 0
+	case KindSecp256k1:
+		return n == 33 || n == 65
+	default:
+		return KindOf(make([]byte, n)) == k
+	}
+}
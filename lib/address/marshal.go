@@ -0,0 +1,30 @@
+package address
+
+// MarshalText encodes the address as its Me string, so it is a string in json.
+func (a Address) MarshalText() ([]byte, error) {
+	if a == Undef {
+		return []byte{}, nil
+	}
+
+	str, err := encode(a, CurrentNetwork)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(str), nil
+}
+
+// UnmarshalText accepts a Me address or a hex eth address.
+func (a *Address) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*a = Undef
+		return nil
+	}
+
+	addr, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*a = addr
+	return nil
+}
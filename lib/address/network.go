@@ -0,0 +1,61 @@
+package address
+
+import (
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Network selects the prefix of address strings.
+type Network byte
+
+const (
+	Mainnet Network = iota
+	Testnet
+	Devnet
+)
+
+var networkPrefixes = map[Network]string{
+	Mainnet: AddrPrefix,
+	Testnet: "Mt",
+	Devnet:  "Md",
+}
+
+var networkNames = map[Network]string{
+	Mainnet: "mainnet",
+	Testnet: "testnet",
+	Devnet:  "devnet",
+}
+
+// CurrentNetwork is the network addresses are encoded for and accepted from.
+var CurrentNetwork = Mainnet
+
+func (n Network) Prefix() string {
+	return networkPrefixes[n]
+}
+
+func (n Network) String() string {
+	if s, ok := networkNames[n]; ok {
+		return s
+	}
+	return "unknown"
+}
+
+// ParseNetwork accepts the network name.
+func ParseNetwork(s string) (Network, error) {
+	for n, name := range networkNames {
+		if strings.EqualFold(s, name) {
+			return n, nil
+		}
+	}
+	return Mainnet, xerrors.Errorf("unknown network %q", s)
+}
+
+func networkOf(prefix string) (Network, bool) {
+	for n, p := range networkPrefixes {
+		if p == prefix {
+			return n, true
+		}
+	}
+	return Mainnet, false
+}
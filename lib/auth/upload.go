@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"golang.org/x/xerrors"
+)
+
+const (
+	DomainName    = "memo"
+	DomainVersion = "1"
+
+	// metadata keys carrying the typed authorization on put
+	MetaAuth = "auth"
+	MetaSign = "auth-sign"
+
+	signatureSize = 65
+)
+
+var uploadTypes = apitypes.Types{
+	"EIP712Domain": {
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+	},
+	"Upload": {
+		{Name: "bucket", Type: "address"},
+		{Name: "object", Type: "string"},
+		{Name: "size", Type: "uint256"},
+		{Name: "contentHash", Type: "bytes32"},
+		{Name: "days", Type: "uint256"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "expiry", Type: "uint256"},
+	},
+}
+
+// Upload authorizes storing one object in a bucket; it is signed as
+// EIP-712 typed data so the signature binds every field and the chain.
+type Upload struct {
+	Bucket      common.Address `json:"bucket"`
+	Object      string         `json:"object"`
+	Size        int64          `json:"size"`
+	ContentHash common.Hash    `json:"contentHash"`
+	Days        int64          `json:"days"`
+	Nonce       uint64         `json:"nonce,string"`
+	ChainID     *big.Int       `json:"chainId"`
+	Expiry      int64          `json:"expiry"`
+}
+
+// NewUpload fills nonce and expiry; the authorization is valid for ttl.
+func NewUpload(bucket common.Address, object string, data []byte, days int64, chainID *big.Int, ttl time.Duration) (*Upload, error) {
+	var buf [8]byte
+	_, err := rand.Read(buf[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &Upload{
+		Bucket:      bucket,
+		Object:      object,
+		Size:        int64(len(data)),
+		ContentHash: crypto.Keccak256Hash(data),
+		Days:        days,
+		Nonce:       binary.BigEndian.Uint64(buf[:]),
+		ChainID:     chainID,
+		Expiry:      time.Now().Add(ttl).Unix(),
+	}, nil
+}
+
+func (u *Upload) TypedData() apitypes.TypedData {
+	return apitypes.TypedData{
+		Types:       uploadTypes,
+		PrimaryType: "Upload",
+		Domain: apitypes.TypedDataDomain{
+			Name:    DomainName,
+			Version: DomainVersion,
+			ChainId: (*math.HexOrDecimal256)(u.ChainID),
+		},
+		Message: apitypes.TypedDataMessage{
+			"bucket":      u.Bucket.Hex(),
+			"object":      u.Object,
+			"size":        math.NewHexOrDecimal256(u.Size),
+			"contentHash": u.ContentHash.Bytes(),
+			"days":        math.NewHexOrDecimal256(u.Days),
+			"nonce":       (*math.HexOrDecimal256)(new(big.Int).SetUint64(u.Nonce)),
+			"expiry":      math.NewHexOrDecimal256(u.Expiry),
+		},
+	}
+}
+
+// Hash returns the EIP-712 digest to be signed.
+func (u *Upload) Hash() ([]byte, error) {
+	if u.ChainID == nil {
+		return nil, xerrors.New("upload auth has no chain id")
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(u.TypedData())
+	if err != nil {
+		return nil, err
+	}
+	return hash, nil
+}
+
+// Metadata encodes the authorization and its signature for object metadata.
+func (u *Upload) Metadata(sig []byte) (map[string]string, error) {
+	buf, err := json.Marshal(u)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		MetaAuth: string(buf),
+		MetaSign: hexutil.Encode(sig),
+	}, nil
+}
+
+// Recover returns the address that signed the authorization.
+func (u *Upload) Recover(sig []byte) (common.Address, error) {
+	if len(sig) != signatureSize {
+		return common.Address{}, xerrors.Errorf("invalid signature length %d", len(sig))
+	}
+
+	hash, err := u.Hash()
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	rsv := make([]byte, signatureSize)
+	copy(rsv, sig)
+	if rsv[signatureSize-1] >= 27 {
+		rsv[signatureSize-1] -= 27
+	}
+
+	pub, err := crypto.SigToPub(hash, rsv)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// Verify checks the signature is from the bucket owner and not expired.
+func (u *Upload) Verify(sig []byte) error {
+	if time.Now().Unix() > u.Expiry {
+		return xerrors.New("upload auth expired")
+	}
+
+	signer, err := u.Recover(sig)
+	if err != nil {
+		return err
+	}
+
+	if signer != u.Bucket {
+		return xerrors.Errorf("upload auth signed by %s, not bucket owner %s", signer, u.Bucket)
+	}
+	return nil
+}
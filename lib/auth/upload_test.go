@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func signedUpload(t *testing.T) (*Upload, []byte) {
+	sk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := NewUpload(crypto.PubkeyToAddress(sk.PublicKey), "a.txt", []byte("hello"), 100, big.NewInt(985), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := u.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := crypto.Sign(hash, sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// as wallets hand it out
+	sig[len(sig)-1] += 27
+
+	return u, sig
+}
+
+func TestUploadRecover(t *testing.T) {
+	u, sig := signedUpload(t)
+
+	signer, err := u.Recover(sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer != u.Bucket {
+		t.Fatalf("recovered %s, want %s", signer, u.Bucket)
+	}
+
+	err = u.Verify(sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every field is bound by the signature
+	tamper := map[string]func(u *Upload){
+		"object":  func(u *Upload) { u.Object = "b.txt" },
+		"size":    func(u *Upload) { u.Size++ },
+		"days":    func(u *Upload) { u.Days = 1000 },
+		"nonce":   func(u *Upload) { u.Nonce++ },
+		"chainId": func(u *Upload) { u.ChainID = big.NewInt(1) },
+		"expiry":  func(u *Upload) { u.Expiry++ },
+	}
+	for name, fn := range tamper {
+		c := *u
+		fn(&c)
+		if c.Verify(sig) == nil {
+			t.Errorf("%s changed, still verifies", name)
+		}
+	}
+}
+
+func TestUploadRejects(t *testing.T) {
+	u, sig := signedUpload(t)
+
+	expired := *u
+	expired.Expiry = time.Now().Add(-time.Minute).Unix()
+	if expired.Verify(sig) == nil {
+		t.Error("expired auth verifies")
+	}
+
+	noChain := *u
+	noChain.ChainID = nil
+	if _, err := noChain.Hash(); err == nil {
+		t.Error("hashed an auth without chain id")
+	}
+
+	if _, err := u.Recover(sig[:64]); err == nil {
+		t.Error("recovered from a short signature")
+	}
+}
@@ -0,0 +1,356 @@
+package kv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/memoio/memo-client/lib/types/store"
+	"golang.org/x/xerrors"
+)
+
+var _ store.KVStore = (*fileStore)(nil)
+var _ store.KeyLocker = (*fileStore)(nil)
+
+// fileStore keeps each value in its own file named by the hex key; it
+// serves the small amount of state the client has.
+type fileStore struct {
+	sync.RWMutex
+	path string
+}
+
+func NewKVStore(path string) (store.KVStore, error) {
+	err := os.MkdirAll(path, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileStore{
+		path: path,
+	}, nil
+}
+
+func (s *fileStore) file(key []byte) string {
+	return filepath.Join(s.path, hex.EncodeToString(key))
+}
+
+func (s *fileStore) Put(key, value []byte) error {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.put(key, value)
+}
+
+func (s *fileStore) put(key, value []byte) error {
+	if len(key) == 0 {
+		return xerrors.New("empty key")
+	}
+
+	// atomic write: write a hidden temporary file then move it into place
+	f, err := ioutil.TempFile(s.path, ".tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(value)
+	if err == nil {
+		err = f.Sync()
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	f.Close()
+
+	return os.Rename(f.Name(), s.file(key))
+}
+
+func (s *fileStore) Get(key []byte) ([]byte, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.get(key)
+}
+
+func (s *fileStore) get(key []byte) ([]byte, error) {
+	val, err := ioutil.ReadFile(s.file(key))
+	if os.IsNotExist(err) {
+		return nil, store.ErrNotFound
+	}
+	return val, err
+}
+
+func (s *fileStore) Has(key []byte) (bool, error) {
+	_, err := s.Get(key)
+	if err == store.ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *fileStore) Delete(key []byte) error {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.delete(key)
+}
+
+func (s *fileStore) delete(key []byte) error {
+	err := os.Remove(s.file(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// GetNext returns the counter at key and increases it; bandwidth is not
+// needed as there is no lease to amortize.
+func (s *fileStore) GetNext(key []byte, bandwidth int) (uint64, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	var next uint64
+	val, err := s.get(key)
+	switch err {
+	case nil:
+		if len(val) != 8 {
+			return 0, xerrors.Errorf("invalid counter at %s", key)
+		}
+		next = binary.BigEndian.Uint64(val)
+	case store.ErrNotFound:
+	default:
+		return 0, err
+	}
+
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, next+1)
+	err = s.put(key, buf)
+	if err != nil {
+		return 0, err
+	}
+
+	return next, nil
+}
+
+// keys returns the sorted keys under prefix.
+func (s *fileStore) keys(prefix []byte) ([][]byte, error) {
+	files, err := ioutil.ReadDir(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]byte, 0, len(files))
+	for _, f := range files {
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+
+		key, err := hex.DecodeString(f.Name())
+		if err != nil {
+			continue
+		}
+
+		if bytes.HasPrefix(key, prefix) {
+			out = append(out, key)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return bytes.Compare(out[i], out[j]) < 0
+	})
+
+	return out, nil
+}
+
+// Iter calls fn on each pair under prefix, stopping at the first error;
+// it returns the number of pairs visited.
+func (s *fileStore) Iter(prefix []byte, fn func(k, v []byte) error) int64 {
+	s.RLock()
+	defer s.RUnlock()
+
+	keys, err := s.keys(prefix)
+	if err != nil {
+		return 0
+	}
+
+	var n int64
+	for _, key := range keys {
+		val, err := s.get(key)
+		if err != nil {
+			continue
+		}
+
+		if fn(key, val) != nil {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+func (s *fileStore) IterKeys(prefix []byte, fn func(k []byte) error) int64 {
+	s.RLock()
+	defer s.RUnlock()
+
+	keys, err := s.keys(prefix)
+	if err != nil {
+		return 0
+	}
+
+	var n int64
+	for _, key := range keys {
+		if fn(key) != nil {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+func (s *fileStore) Size() store.DiskStats {
+	s.RLock()
+	defer s.RUnlock()
+
+	ds := store.DiskStats{
+		Path: s.path,
+	}
+
+	files, err := ioutil.ReadDir(s.path)
+	if err != nil {
+		return ds
+	}
+
+	for _, f := range files {
+		ds.Used += uint64(f.Size())
+	}
+	return ds
+}
+
+func (s *fileStore) Sync() error {
+	return nil
+}
+
+func (s *fileStore) Close() error {
+	return nil
+}
+
+// LockKey takes an exclusive file lock on a hidden lock file of key, so
+// other processes using the same directory wait for unlock.
+func (s *fileStore) LockKey(key []byte) (func(), error) {
+	if len(key) == 0 {
+		return nil, xerrors.New("empty key")
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.path, ".lock-"+hex.EncodeToString(key)), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lockFile(f)
+	if err != nil {
+		f.Close()
+		return nil, xerrors.Errorf("lock %x: %w", key, err)
+	}
+
+	return func() {
+		_ = unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// NewTxnStore buffers writes until Commit.
+func (s *fileStore) NewTxnStore(update bool) (store.TxnStore, error) {
+	return &txn{
+		s:      s,
+		update: update,
+		puts:   make(map[string][]byte),
+	}, nil
+}
+
+type txn struct {
+	s      *fileStore
+	update bool
+
+	// a nil value marks a delete
+	puts map[string][]byte
+}
+
+func (t *txn) Put(key, value []byte) error {
+	if !t.update {
+		return xerrors.New("read only transaction")
+	}
+	if len(key) == 0 {
+		return xerrors.New("empty key")
+	}
+
+	t.puts[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+func (t *txn) Get(key []byte) ([]byte, error) {
+	val, ok := t.puts[string(key)]
+	if ok {
+		if val == nil {
+			return nil, store.ErrNotFound
+		}
+		return val, nil
+	}
+	return t.s.Get(key)
+}
+
+func (t *txn) Has(key []byte) (bool, error) {
+	_, err := t.Get(key)
+	if err == store.ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (t *txn) Delete(key []byte) error {
+	if !t.update {
+		return xerrors.New("read only transaction")
+	}
+
+	t.puts[string(key)] = nil
+	return nil
+}
+
+func (t *txn) Size() store.DiskStats {
+	return t.s.Size()
+}
+
+func (t *txn) Close() error {
+	t.Discard()
+	return nil
+}
+
+func (t *txn) Commit() error {
+	t.s.Lock()
+	defer t.s.Unlock()
+
+	for key, val := range t.puts {
+		var err error
+		if val == nil {
+			err = t.s.delete([]byte(key))
+		} else {
+			err = t.s.put([]byte(key), val)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	t.puts = make(map[string][]byte)
+	return nil
+}
+
+func (t *txn) Discard() {
+	t.puts = make(map[string][]byte)
+}
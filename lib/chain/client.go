@@ -0,0 +1,51 @@
+package chain
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"golang.org/x/xerrors"
+)
+
+// Client is a connection to the chain of a profile.
+type Client struct {
+	*ethclient.Client
+
+	profile Profile
+	chainID *big.Int
+}
+
+// Dial connects to the rpc of p and checks that it serves the chain of p.
+func Dial(ctx context.Context, p *Profile) (*Client, error) {
+	ec, err := ethclient.DialContext(ctx, p.RPC)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID, err := ec.ChainID(ctx)
+	if err != nil {
+		ec.Close()
+		return nil, xerrors.Errorf("get chain id from %s: %w", p.RPC, err)
+	}
+
+	if p.ChainID != 0 && (!chainID.IsUint64() || chainID.Uint64() != p.ChainID) {
+		ec.Close()
+		return nil, xerrors.Errorf("%s is chain %s, profile expects %d", p.RPC, chainID, p.ChainID)
+	}
+
+	return &Client{
+		Client:  ec,
+		profile: *p,
+		chainID: chainID,
+	}, nil
+}
+
+func (c *Client) Profile() Profile {
+	return c.profile
+}
+
+// ChainID returns the chain id read when dialing.
+func (c *Client) ChainID() *big.Int {
+	return new(big.Int).Set(c.chainID)
+}
@@ -0,0 +1,6 @@
+// Package contracts has the bindings of the MEMO token and the storage
+// deposit contract.
+package contracts
+
+//go:generate abigen --abi erc20.abi --pkg contracts --type ERC20 --out erc20.go
+//go:generate abigen --abi store.abi --pkg contracts --type Store --out store.go
@@ -0,0 +1,44 @@
+package chain
+
+import (
+	"math/big"
+
+	"golang.org/x/xerrors"
+)
+
+const defaultFundMargin = 0.1
+
+// FundConfig bounds the deposits made on their own when the storage
+// balance is short.
+type FundConfig struct {
+	// Max is the most MEMO deposited at once, auto funding is off without it
+	Max string `json:"max,omitempty"`
+	// Margin is deposited on top of the shortfall, as a fraction of it
+	Margin float64 `json:"margin,omitempty"`
+}
+
+func (f FundConfig) margin() float64 {
+	if f.Margin <= 0 {
+		return defaultFundMargin
+	}
+	return f.Margin
+}
+
+// FundAmount returns the deposit covering shortfall plus the margin,
+// failing when it is above the maximum.
+func (f FundConfig) FundAmount(shortfall *big.Int) (*big.Int, error) {
+	if f.Max == "" {
+		return nil, xerrors.New("no maximum for auto funding is configured")
+	}
+
+	max, err := ParseAmount(f.Max)
+	if err != nil {
+		return nil, xerrors.Errorf("auto fund max: %w", err)
+	}
+
+	amount := new(big.Int).Add(shortfall, mulFloat(shortfall, f.margin()))
+	if amount.Cmp(max) > 0 {
+		return nil, xerrors.Errorf("deposit of %s MEMO is above the auto fund max %s MEMO", FormatAmount(amount), FormatAmount(max))
+	}
+	return amount, nil
+}
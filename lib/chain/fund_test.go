@@ -0,0 +1,44 @@
+package chain
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFundAmount(t *testing.T) {
+	cases := []struct {
+		name      string
+		cfg       FundConfig
+		shortfall string // automemo
+		want      string // automemo, empty when the deposit is refused
+	}{
+		{"default margin", FundConfig{Max: "10"}, "1000000000000000000", "1100000000000000000"},
+		{"set margin", FundConfig{Max: "10", Margin: 0.5}, "1000000000000000000", "1500000000000000000"},
+		{"negative margin", FundConfig{Max: "10", Margin: -1}, "1000000000000000000", "1100000000000000000"},
+		{"at max", FundConfig{Max: "1.1"}, "1000000000000000000", "1100000000000000000"},
+		{"above max", FundConfig{Max: "1"}, "1000000000000000000", ""},
+		{"margin above max", FundConfig{Max: "1.09"}, "1000000000000000000", ""},
+		{"small shortfall", FundConfig{Max: "1"}, "10", "11"},
+		{"no max", FundConfig{}, "1", ""},
+		{"bad max", FundConfig{Max: "-1"}, "1", ""},
+	}
+
+	for _, c := range cases {
+		shortfall, _ := new(big.Int).SetString(c.shortfall, 10)
+
+		v, err := c.cfg.FundAmount(shortfall)
+		if c.want == "" {
+			if err == nil {
+				t.Fatalf("%s: deposit of %s allowed", c.name, v)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+		if v.String() != c.want {
+			t.Fatalf("%s: got %s, want %s", c.name, v, c.want)
+		}
+	}
+}
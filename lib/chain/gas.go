@@ -0,0 +1,152 @@
+package chain
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"golang.org/x/xerrors"
+)
+
+const (
+	defaultGasMultiplier   = 1.2
+	defaultPriceMultiplier = 1.0
+)
+
+// GasConfig tunes gas estimation and fees; zero values take the defaults
+// and zero caps are no caps.
+type GasConfig struct {
+	// GasMultiplier is applied to the estimated gas limit
+	GasMultiplier float64 `json:"gasMultiplier,omitempty"`
+	MaxGasLimit   uint64  `json:"maxGasLimit,omitempty"`
+
+	// PriceMultiplier is applied to the suggested gas price or tip
+	PriceMultiplier float64 `json:"priceMultiplier,omitempty"`
+	// MaxGasPrice caps the gas price, or the fee cap of EIP-1559 txs, in wei
+	MaxGasPrice uint64 `json:"maxGasPrice,omitempty"`
+	MaxGasTip   uint64 `json:"maxGasTip,omitempty"`
+
+	// Legacy sends legacy txs even when the chain supports EIP-1559
+	Legacy bool `json:"legacy,omitempty"`
+}
+
+func (g GasConfig) gasMultiplier() float64 {
+	if g.GasMultiplier <= 0 {
+		return defaultGasMultiplier
+	}
+	return g.GasMultiplier
+}
+
+func (g GasConfig) priceMultiplier() float64 {
+	if g.PriceMultiplier <= 0 {
+		return defaultPriceMultiplier
+	}
+	return g.PriceMultiplier
+}
+
+// mulFloat multiplies v by the decimal value of m, so that 0.1 of a
+// round amount stays round, and truncates the result.
+func mulFloat(v *big.Int, m float64) *big.Int {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(m, 'g', -1, 64))
+	r.Mul(r, new(big.Rat).SetInt(v))
+	return new(big.Int).Quo(r.Num(), r.Denom())
+}
+
+// capAt returns v, or max when it is set and v is above it.
+func capAt(v *big.Int, max uint64) *big.Int {
+	if max != 0 && v.Cmp(new(big.Int).SetUint64(max)) > 0 {
+		return new(big.Int).SetUint64(max)
+	}
+	return v
+}
+
+// gasBackend is what the bindings use; it scales the estimated gas limit.
+type gasBackend struct {
+	*Client
+}
+
+var _ bind.ContractBackend = gasBackend{}
+
+func (b gasBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return b.Client.EstimateGasLimit(ctx, call)
+}
+
+// EstimateGasLimit estimates the gas of call with the configured multiplier and cap.
+func (c *Client) EstimateGasLimit(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	gas, err := c.Client.EstimateGas(ctx, call)
+	if err != nil {
+		return 0, err
+	}
+
+	cfg := c.profile.Gas
+	limit := uint64(float64(gas) * cfg.gasMultiplier())
+	if cfg.MaxGasLimit != 0 && limit > cfg.MaxGasLimit {
+		if gas > cfg.MaxGasLimit {
+			return 0, xerrors.Errorf("estimated gas %d is above the cap %d", gas, cfg.MaxGasLimit)
+		}
+		limit = cfg.MaxGasLimit
+	}
+	return limit, nil
+}
+
+// Fees are the fees of a tx: GasPrice for legacy txs, GasTipCap and
+// GasFeeCap for EIP-1559 txs.
+type Fees struct {
+	GasPrice  *big.Int
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+}
+
+// Dynamic tells whether the fees are for an EIP-1559 tx.
+func (f Fees) Dynamic() bool {
+	return f.GasFeeCap != nil
+}
+
+// SuggestFees suggests EIP-1559 fees when the chain has London enabled,
+// otherwise a legacy gas price.
+func (c *Client) SuggestFees(ctx context.Context) (Fees, error) {
+	cfg := c.profile.Gas
+
+	if !cfg.Legacy {
+		head, err := c.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return Fees{}, err
+		}
+
+		if head.BaseFee != nil {
+			tip, err := c.SuggestGasTipCap(ctx)
+			if err == nil {
+				tip = capAt(mulFloat(tip, cfg.priceMultiplier()), cfg.MaxGasTip)
+
+				// room for the base fee to double
+				feeCap := new(big.Int).Add(tip, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+				feeCap = capAt(feeCap, cfg.MaxGasPrice)
+				if feeCap.Cmp(head.BaseFee) < 0 {
+					return Fees{}, xerrors.Errorf("base fee %s is above the cap %d", head.BaseFee, cfg.MaxGasPrice)
+				}
+				if tip.Cmp(feeCap) > 0 {
+					tip = feeCap
+				}
+
+				return Fees{GasTipCap: tip, GasFeeCap: feeCap}, nil
+			}
+			// nodes without eth_maxPriorityFeePerGas take legacy txs
+		}
+	}
+
+	price, err := c.SuggestGasPrice(ctx)
+	if err != nil {
+		return Fees{}, err
+	}
+
+	return Fees{GasPrice: capAt(mulFloat(price, cfg.priceMultiplier()), cfg.MaxGasPrice)}, nil
+}
+
+// SetFees sets the fees on opts.
+func (f Fees) SetFees(opts *bind.TransactOpts) {
+	opts.GasPrice = f.GasPrice
+	opts.GasTipCap = f.GasTipCap
+	opts.GasFeeCap = f.GasFeeCap
+}
@@ -0,0 +1,58 @@
+package chain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+func TestSuggestFeesCaps(t *testing.T) {
+	cases := []struct {
+		name string
+		eth  *fakeEth
+		gas  GasConfig
+
+		price, tip, feeCap int64
+		fail               bool
+	}{
+		{name: "legacy", eth: &fakeEth{price: 100}, price: 100},
+		{name: "legacy multiplier", eth: &fakeEth{price: 100}, gas: GasConfig{PriceMultiplier: 1.5}, price: 150},
+		{name: "legacy capped", eth: &fakeEth{price: 100}, gas: GasConfig{PriceMultiplier: 1.5, MaxGasPrice: 120}, price: 120},
+		{name: "dynamic", eth: &fakeEth{baseFee: big.NewInt(50), tip: 10}, tip: 10, feeCap: 110},
+		{name: "tip capped", eth: &fakeEth{baseFee: big.NewInt(50), tip: 10}, gas: GasConfig{MaxGasTip: 4}, tip: 4, feeCap: 104},
+		{name: "fee cap capped", eth: &fakeEth{baseFee: big.NewInt(50), tip: 10}, gas: GasConfig{MaxGasPrice: 60}, tip: 10, feeCap: 60},
+		{name: "tip within fee cap", eth: &fakeEth{baseFee: big.NewInt(50), tip: 80}, gas: GasConfig{MaxGasPrice: 70}, tip: 70, feeCap: 70},
+		{name: "base fee above cap", eth: &fakeEth{baseFee: big.NewInt(50), tip: 10}, gas: GasConfig{MaxGasPrice: 40}, fail: true},
+		{name: "forced legacy", eth: &fakeEth{baseFee: big.NewInt(50), tip: 10, price: 70}, gas: GasConfig{Legacy: true}, price: 70},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			m := newFakeManager(t, c.eth)
+			m.c.profile.Gas = c.gas
+
+			fees, err := m.c.SuggestFees(context.Background())
+			if c.fail {
+				if err == nil {
+					t.Fatalf("got %+v, want an error", fees)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if c.price != 0 {
+				if fees.Dynamic() || fees.GasPrice.Int64() != c.price {
+					t.Fatalf("got %+v, want gas price %d", fees, c.price)
+				}
+				return
+			}
+
+			if !fees.Dynamic() || fees.GasTipCap.Int64() != c.tip || fees.GasFeeCap.Int64() != c.feeCap {
+				t.Fatalf("got tip %s fee cap %s, want %d %d", fees.GasTipCap, fees.GasFeeCap, c.tip, c.feeCap)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package chain
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/memoio/memo-client/lib/types/store"
+	"golang.org/x/xerrors"
+)
+
+// state key prefix of the next nonce of each account
+const noncePrefix = "nonce/"
+
+func nonceKey(from common.Address) []byte {
+	return []byte(noncePrefix + from.Hex())
+}
+
+// lockNonce holds the nonce of from against other goroutines and, when
+// the state store is shared, against other processes; the whole
+// load-increment-store sequence must run under it.
+func (m *TxManager) lockNonce(from common.Address) (func(), error) {
+	m.lk.Lock()
+
+	kl, ok := m.ds.(store.KeyLocker)
+	if !ok {
+		return m.lk.Unlock, nil
+	}
+
+	unlock, err := kl.LockKey(nonceKey(from))
+	if err != nil {
+		m.lk.Unlock()
+		return nil, err
+	}
+
+	return func() {
+		unlock()
+		m.lk.Unlock()
+	}, nil
+}
+
+func (m *TxManager) loadNonce(from common.Address) (uint64, bool, error) {
+	val, err := m.ds.Get(nonceKey(from))
+	if err == store.ErrNotFound {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	if len(val) != 8 {
+		return 0, false, xerrors.Errorf("invalid nonce of %s in state", from)
+	}
+	return binary.BigEndian.Uint64(val), true, nil
+}
+
+func (m *TxManager) storeNonce(from common.Address, next uint64) error {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, next)
+	return m.ds.Put(nonceKey(from), buf)
+}
+
+// trackedNonces returns the nonces of the tracked txs of from and the
+// nonces still reserved for it.
+func (m *TxManager) trackedNonces(from common.Address) (map[uint64]bool, error) {
+	ps, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[uint64]bool, len(ps))
+	for _, p := range ps {
+		if p.From == from {
+			res[p.Nonce] = true
+		}
+	}
+
+	for _, r := range m.reservations(from) {
+		if !m.expired(r) {
+			res[r.Nonce] = true
+		}
+	}
+	return res, nil
+}
+
+// SyncNonce reconciles the stored next nonce of from with the chain. The
+// nonces handed out above the chain's pending nonce that no tracked tx
+// uses are gaps, which block every later tx; they are returned and the
+// next nonce rewinds to the first of them.
+func (m *TxManager) SyncNonce(ctx context.Context, from common.Address) ([]uint64, error) {
+	unlock, err := m.lockNonce(from)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return m.syncNonce(ctx, from)
+}
+
+func (m *TxManager) syncNonce(ctx context.Context, from common.Address) ([]uint64, error) {
+	pending, err := m.c.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	// reserved nonces below the chain's were sent from elsewhere, expired
+	// ones become gaps
+	for _, r := range m.reservations(from) {
+		if r.Nonce < pending || m.expired(r) {
+			err = m.ds.Delete(reservedKey(from, r.Nonce))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	stored, ok, err := m.loadNonce(from)
+	if err != nil {
+		return nil, err
+	}
+
+	next := pending
+	var gaps []uint64
+	if ok && stored > pending {
+		tracked, err := m.trackedNonces(from)
+		if err != nil {
+			return nil, err
+		}
+
+		// txs sent moments ago may not be in the node's pending view yet
+		for n := pending; n < stored; n++ {
+			if !tracked[n] {
+				gaps = append(gaps, n)
+			}
+		}
+
+		next = stored
+		if len(gaps) > 0 {
+			next = gaps[0]
+		}
+	}
+
+	err = m.storeNonce(from, next)
+	if err != nil {
+		return nil, err
+	}
+
+	m.synced[from] = true
+	return gaps, nil
+}
+
+// NextNonce hands out the next nonce of from, syncing with the chain on
+// first use; nonces of tracked txs are skipped.
+func (m *TxManager) NextNonce(ctx context.Context, from common.Address) (uint64, error) {
+	unlock, err := m.lockNonce(from)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	return m.nextNonce(ctx, from)
+}
+
+func (m *TxManager) nextNonce(ctx context.Context, from common.Address) (uint64, error) {
+	if !m.synced[from] {
+		_, err := m.syncNonce(ctx, from)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	next, _, err := m.loadNonce(from)
+	if err != nil {
+		return 0, err
+	}
+
+	tracked, err := m.trackedNonces(from)
+	if err != nil {
+		return 0, err
+	}
+
+	for tracked[next] {
+		next++
+	}
+
+	err = m.storeNonce(from, next+1)
+	if err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+// SetNonce sets the nonce of opts to the next nonce of its account.
+func (m *TxManager) SetNonce(ctx context.Context, opts *bind.TransactOpts) error {
+	n, err := m.NextNonce(ctx, opts.From)
+	if err != nil {
+		return err
+	}
+
+	opts.Nonce = new(big.Int).SetUint64(n)
+	return nil
+}
+
+// ReleaseNonce gives back nonce when it was the last handed out and its
+// tx did not go out.
+func (m *TxManager) ReleaseNonce(from common.Address, nonce uint64) {
+	unlock, err := m.lockNonce(from)
+	if err != nil {
+		return
+	}
+	defer unlock()
+
+	_ = m.ds.Delete(reservedKey(from, nonce))
+
+	next, ok, err := m.loadNonce(from)
+	if err == nil && ok && next == nonce+1 {
+		_ = m.storeNonce(from, nonce)
+	}
+}
@@ -0,0 +1,266 @@
+package chain
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/memoio/memo-client/lib/backend/kv"
+)
+
+// two managers on one state dir stand for two client processes
+func TestNextNonceSharedState(t *testing.T) {
+	dir := t.TempDir()
+	from := common.HexToAddress("0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf")
+
+	const perManager = 50
+
+	ms := make([]*TxManager, 2)
+	for i := range ms {
+		ds, err := kv.NewKVStore(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ms[i] = NewTxManager(nil, ds)
+		// no chain to sync with
+		ms[i].synced[from] = true
+	}
+
+	err := ms[0].storeNonce(from, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	got := make([][]uint64, len(ms))
+	errs := make([]error, len(ms))
+	for i, m := range ms {
+		wg.Add(1)
+		go func(i int, m *TxManager) {
+			defer wg.Done()
+
+			for j := 0; j < perManager; j++ {
+				n, err := m.NextNonce(context.Background(), from)
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				got[i] = append(got[i], n)
+			}
+		}(i, m)
+	}
+	wg.Wait()
+
+	seen := make(map[uint64]bool)
+	for i := range ms {
+		if errs[i] != nil {
+			t.Fatalf("manager %d: %s", i, errs[i])
+		}
+
+		for _, n := range got[i] {
+			if seen[n] {
+				t.Fatalf("nonce %d handed out twice", n)
+			}
+			seen[n] = true
+		}
+	}
+
+	for n := uint64(0); n < 2*perManager; n++ {
+		if !seen[n] {
+			t.Fatalf("nonce %d skipped", n)
+		}
+	}
+
+	next, _, err := ms[1].loadNonce(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != 2*perManager {
+		t.Fatalf("stored next nonce %d, want %d", next, 2*perManager)
+	}
+}
+
+func TestReleaseNonceSharedState(t *testing.T) {
+	dir := t.TempDir()
+	from := common.HexToAddress("0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf")
+
+	ms := make([]*TxManager, 2)
+	for i := range ms {
+		ds, err := kv.NewKVStore(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ms[i] = NewTxManager(nil, ds)
+		ms[i].synced[from] = true
+	}
+
+	ctx := context.Background()
+	a, err := ms[0].NextNonce(ctx, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ms[1].NextNonce(ctx, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a is no longer the last one handed out, so it stays taken
+	ms[0].ReleaseNonce(from, a)
+
+	c, err := ms[0].NextNonce(ctx, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c == a || c == b {
+		t.Fatalf("nonce %d handed out again after %d, %d", c, a, b)
+	}
+
+	ms[0].ReleaseNonce(from, c)
+
+	d, err := ms[1].NextNonce(ctx, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != c {
+		t.Fatalf("released nonce %d not reused, got %d", c, d)
+	}
+}
+
+func TestReserveNonce(t *testing.T) {
+	ds, err := kv.NewKVStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := common.HexToAddress("0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf")
+
+	m := NewTxManager(nil, ds)
+	m.synced[from] = true
+
+	ctx := context.Background()
+	a, err := m.ReserveNonce(ctx, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a fresh process no longer takes the reserved nonce for a gap
+	tracked, err := NewTxManager(nil, ds).trackedNonces(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tracked[a] {
+		t.Fatalf("reserved nonce %d not counted as used", a)
+	}
+
+	b, err := m.NextNonce(ctx, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b == a {
+		t.Fatalf("reserved nonce %d handed out again", a)
+	}
+
+	m.ReleaseNonce(from, a)
+	if len(m.reservations(from)) != 0 {
+		t.Fatal("released nonce still reserved")
+	}
+}
+
+func TestReservationExpires(t *testing.T) {
+	ds, err := kv.NewKVStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := common.HexToAddress("0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf")
+
+	m := NewTxManager(nil, ds)
+	m.synced[from] = true
+
+	n, err := m.ReserveNonce(context.Background(), from)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rs := m.Reservations()
+	if len(rs) != 1 || rs[0].From != from || rs[0].Nonce != n {
+		t.Fatalf("reservations %v, want nonce %d of %s", rs, n, from)
+	}
+	if time.Since(rs[0].At) > time.Minute {
+		t.Fatalf("reserved at %s", rs[0].At)
+	}
+
+	m.ReserveTTL = time.Nanosecond
+	time.Sleep(time.Millisecond)
+
+	tracked, err := m.trackedNonces(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tracked[n] {
+		t.Fatalf("expired reservation of %d still holds it", n)
+	}
+}
+
+func TestUnreserve(t *testing.T) {
+	ds, err := kv.NewKVStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := common.HexToAddress("0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf")
+
+	m := NewTxManager(nil, ds)
+	m.synced[from] = true
+
+	ctx := context.Background()
+	a, err := m.ReserveNonce(ctx, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := m.ReserveNonce(ctx, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an earlier one is left for the next sync to find as a gap
+	err = m.Unreserve(from, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracked, err := m.trackedNonces(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tracked[a] {
+		t.Fatalf("nonce %d still reserved", a)
+	}
+	if m.synced[from] {
+		t.Fatal("no sync pending after unreserving a gap")
+	}
+
+	// the last one handed out is given back at once
+	err = m.Unreserve(from, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next, _, err := m.loadNonce(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != b {
+		t.Fatalf("next nonce %d, want %d back", next, b)
+	}
+
+	if m.Unreserve(from, a) == nil {
+		t.Fatal("unreserved a nonce twice")
+	}
+}
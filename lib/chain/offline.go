@@ -0,0 +1,127 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/memoio/memo-client/lib/chain/contracts"
+	"golang.org/x/xerrors"
+)
+
+// UnsignedTx carries a built tx to an offline signer. Legacy txs keep
+// their chain id only in the signature, so it travels alongside.
+type UnsignedTx struct {
+	ChainID *big.Int           `json:"chainID"`
+	From    common.Address     `json:"from"`
+	Desc    string             `json:"desc,omitempty"`
+	Tx      *types.Transaction `json:"tx"`
+}
+
+func NewUnsignedTx(chainID *big.Int, from common.Address, desc string, tx *types.Transaction) *UnsignedTx {
+	return &UnsignedTx{
+		ChainID: chainID,
+		From:    from,
+		Desc:    desc,
+		Tx:      tx,
+	}
+}
+
+// ParseUnsignedTx decodes and checks the json of an UnsignedTx.
+func ParseUnsignedTx(buf []byte) (*UnsignedTx, error) {
+	ut := new(UnsignedTx)
+	err := json.Unmarshal(buf, ut)
+	if err != nil {
+		return nil, xerrors.Errorf("decode unsigned tx: %w", err)
+	}
+
+	if ut.Tx == nil || ut.ChainID == nil {
+		return nil, xerrors.New("unsigned tx has no tx or chain id")
+	}
+
+	if ut.Tx.Type() != types.LegacyTxType && ut.Tx.ChainId().Cmp(ut.ChainID) != 0 {
+		return nil, xerrors.Errorf("tx is for chain %s, not %s", ut.Tx.ChainId(), ut.ChainID)
+	}
+
+	v, r, s := ut.Tx.RawSignatureValues()
+	if v.Sign() != 0 || r.Sign() != 0 || s.Sign() != 0 {
+		return nil, xerrors.New("tx is already signed")
+	}
+
+	return ut, nil
+}
+
+// CheckApprove errors unless tx only calls approve(address,uint256) on
+// token, the one tx the gateway relays.
+func CheckApprove(tx *types.Transaction, token common.Address) error {
+	if tx.To() == nil || *tx.To() != token {
+		return xerrors.Errorf("tx is not to the token contract %s", token)
+	}
+
+	if tx.Value().Sign() != 0 {
+		return xerrors.New("approve tx carries value")
+	}
+
+	parsed, err := contracts.ERC20MetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+
+	approve := parsed.Methods["approve"]
+	data := tx.Data()
+	if len(data) < 4 || !bytes.Equal(data[:4], approve.ID) {
+		return xerrors.New("tx does not call approve")
+	}
+
+	_, err = approve.Inputs.Unpack(data[4:])
+	if err != nil {
+		return xerrors.Errorf("decode approve args: %w", err)
+	}
+	return nil
+}
+
+// DecodeCall describes the call in tx data as method(arg=value, ...) when
+// it is a method of the token or store contract; amounts are in MEMO.
+// Empty data is a plain transfer and gives "".
+func DecodeCall(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", nil
+	}
+	if len(data) < 4 {
+		return "", xerrors.New("tx data is too short for a call")
+	}
+
+	for _, md := range []*bind.MetaData{contracts.ERC20MetaData, contracts.StoreMetaData} {
+		parsed, err := md.GetAbi()
+		if err != nil {
+			return "", err
+		}
+
+		method, err := parsed.MethodById(data[:4])
+		if err != nil {
+			continue
+		}
+
+		args, err := method.Inputs.Unpack(data[4:])
+		if err != nil {
+			return "", xerrors.Errorf("decode %s args: %w", method.Name, err)
+		}
+
+		parts := make([]string, len(args))
+		for i, arg := range args {
+			v := fmt.Sprint(arg)
+			if n, ok := arg.(*big.Int); ok {
+				v = FormatAmount(n) + " MEMO"
+			}
+			parts[i] = method.Inputs[i].Name + "=" + v
+		}
+		return method.Name + "(" + strings.Join(parts, ", ") + ")", nil
+	}
+
+	return "", xerrors.Errorf("unknown call %#x", data[:4])
+}
@@ -0,0 +1,97 @@
+package chain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/memoio/memo-client/lib/chain/contracts"
+)
+
+func TestCheckApprove(t *testing.T) {
+	token := common.HexToAddress("0x01")
+	other := common.HexToAddress("0x02")
+
+	parsed, err := contracts.ERC20MetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	approve, err := parsed.Pack("approve", other, big.NewInt(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transfer, err := parsed.Pack("transfer", other, big.NewInt(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newTx := func(to *common.Address, value int64, data []byte) *types.Transaction {
+		return types.NewTx(&types.LegacyTx{
+			To:       to,
+			Value:    big.NewInt(value),
+			Gas:      60000,
+			GasPrice: big.NewInt(1),
+			Data:     data,
+		})
+	}
+
+	err = CheckApprove(newTx(&token, 0, approve), token)
+	if err != nil {
+		t.Fatalf("approve rejected: %s", err)
+	}
+
+	bad := map[string]*types.Transaction{
+		"other contract": newTx(&other, 0, approve),
+		"create":         newTx(nil, 0, approve),
+		"value":          newTx(&token, 1, approve),
+		"transfer":       newTx(&token, 0, transfer),
+		"no data":        newTx(&token, 0, nil),
+		"short args":     newTx(&token, 0, approve[:20]),
+	}
+	for name, tx := range bad {
+		if CheckApprove(tx, token) == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+}
+
+func TestDecodeCall(t *testing.T) {
+	parsed, err := contracts.ERC20MetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spender := common.HexToAddress("0x02")
+	data, err := parsed.Pack("approve", spender, big.NewInt(1500000000000000000))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	call, err := DecodeCall(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "approve(spender=" + spender.Hex() + ", amount=1.5 MEMO)"
+	if call != want {
+		t.Fatalf("got %s, want %s", call, want)
+	}
+
+	call, err = DecodeCall(nil)
+	if err != nil || call != "" {
+		t.Fatalf("plain transfer gave %q, %v", call, err)
+	}
+
+	for name, data := range map[string][]byte{
+		"short":      {0x09, 0x5e},
+		"unknown":    {0xde, 0xad, 0xbe, 0xef},
+		"short args": data[:20],
+	} {
+		_, err := DecodeCall(data)
+		if err == nil {
+			t.Errorf("%s: decoded", name)
+		}
+	}
+}
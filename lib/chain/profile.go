@@ -0,0 +1,76 @@
+package chain
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/xerrors"
+)
+
+// ProfileFile in the repo overrides or adds profiles, keyed by network name.
+const ProfileFile = "chain.json"
+
+// Profile is what the client needs to know about a chain.
+type Profile struct {
+	RPC string `json:"rpc"`
+	// ChainID is checked against the node when set
+	ChainID uint64 `json:"chainID,omitempty"`
+
+	// Token is the MEMO erc20 token, Store the contract taking storage deposits
+	Token common.Address `json:"token"`
+	Store common.Address `json:"store"`
+	// StoreBlock is the block the store contract was deployed at, deposit
+	// logs are searched from it; when unset it is looked up on chain,
+	// which needs a node keeping old state
+	StoreBlock uint64 `json:"storeBlock,omitempty"`
+
+	Gas  GasConfig  `json:"gas,omitempty"`
+	Fund FundConfig `json:"fund,omitempty"`
+}
+
+var defaultProfiles = map[string]Profile{
+	"mainnet": {
+		RPC:     "https://chain.metamemo.one:8501",
+		ChainID: 985,
+		Token:   common.HexToAddress("0xa678920287Eac5b8e81578268469AA2BaaD2eC87"),
+		Store:   common.HexToAddress("0xCcf7b7F747100f3393a75DDf6864589f76F4eA25"),
+	},
+}
+
+// LoadProfile returns the profile of network name, from ProfileFile in
+// repoPath if it has one, otherwise from the built in profiles.
+func LoadProfile(repoPath, name string) (*Profile, error) {
+	profiles := make(map[string]Profile, len(defaultProfiles))
+	for n, p := range defaultProfiles {
+		profiles[n] = p
+	}
+
+	buf, err := os.ReadFile(filepath.Join(repoPath, ProfileFile))
+	switch {
+	case err == nil:
+		var cfg map[string]Profile
+		err = json.Unmarshal(buf, &cfg)
+		if err != nil {
+			return nil, xerrors.Errorf("parse %s: %w", ProfileFile, err)
+		}
+
+		for n, p := range cfg {
+			profiles[n] = p
+		}
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	p, ok := profiles[name]
+	if !ok {
+		return nil, xerrors.Errorf("no chain profile for %s, add it to %s", name, ProfileFile)
+	}
+
+	if p.RPC == "" {
+		return nil, xerrors.Errorf("chain profile %s has no rpc", name)
+	}
+
+	return &p, nil
+}
@@ -0,0 +1,145 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/xerrors"
+)
+
+// state key prefix of the nonces handed out to txs built for offline
+// signing, which are not tracked until they are sent
+const reservedPrefix = "nonce-reserved/"
+
+const defaultReserveTTL = 24 * time.Hour
+
+// Reservation is a nonce handed out to a tx built for offline signing.
+type Reservation struct {
+	From  common.Address
+	Nonce uint64
+	At    time.Time
+}
+
+func reservedKeyPrefix(from common.Address) []byte {
+	return []byte(reservedPrefix + from.Hex() + "/")
+}
+
+func reservedKey(from common.Address, nonce uint64) []byte {
+	return strconv.AppendUint(reservedKeyPrefix(from), nonce, 10)
+}
+
+func parseReservation(k, v []byte) (*Reservation, error) {
+	rest := bytes.TrimPrefix(k, []byte(reservedPrefix))
+	i := bytes.IndexByte(rest, '/')
+	if i < 0 || !common.IsHexAddress(string(rest[:i])) {
+		return nil, xerrors.Errorf("invalid reservation key %s", k)
+	}
+
+	n, err := strconv.ParseUint(string(rest[i+1:]), 10, 64)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid reservation key %s", k)
+	}
+
+	r := &Reservation{
+		From:  common.HexToAddress(string(rest[:i])),
+		Nonce: n,
+	}
+	if len(v) == 8 {
+		r.At = time.Unix(int64(binary.BigEndian.Uint64(v)), 0)
+	}
+	return r, nil
+}
+
+func (m *TxManager) listReservations(prefix []byte) []*Reservation {
+	var res []*Reservation
+	m.ds.Iter(prefix, func(k, v []byte) error {
+		r, err := parseReservation(k, v)
+		if err == nil {
+			res = append(res, r)
+		}
+		return nil
+	})
+	return res
+}
+
+// Reservations lists the reserved nonces of all accounts.
+func (m *TxManager) Reservations() []*Reservation {
+	return m.listReservations([]byte(reservedPrefix))
+}
+
+func (m *TxManager) reservations(from common.Address) []*Reservation {
+	return m.listReservations(reservedKeyPrefix(from))
+}
+
+// Expires returns when r stops holding its nonce.
+func (m *TxManager) Expires(r *Reservation) time.Time {
+	return r.At.Add(m.ReserveTTL)
+}
+
+func (m *TxManager) expired(r *Reservation) bool {
+	return m.ReserveTTL > 0 && time.Now().After(m.Expires(r))
+}
+
+// ReserveNonce hands out the next nonce of from for a tx that is signed
+// and sent later; until it is tracked or ReserveTTL passes, syncing does
+// not count the nonce as a gap.
+func (m *TxManager) ReserveNonce(ctx context.Context, from common.Address) (uint64, error) {
+	unlock, err := m.lockNonce(from)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	n, err := m.nextNonce(ctx, from)
+	if err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(time.Now().Unix()))
+	err = m.ds.Put(reservedKey(from, n), buf)
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Unreserve drops the reservation of nonce for a built tx that will not
+// be sent. The nonce is given back when it was the last handed out, else
+// the next sync reports it as a gap to reuse.
+func (m *TxManager) Unreserve(from common.Address, nonce uint64) error {
+	unlock, err := m.lockNonce(from)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	has, err := m.ds.Has(reservedKey(from, nonce))
+	if err != nil {
+		return err
+	}
+	if !has {
+		return xerrors.Errorf("nonce %d of %s is not reserved", nonce, from)
+	}
+
+	err = m.ds.Delete(reservedKey(from, nonce))
+	if err != nil {
+		return err
+	}
+
+	next, ok, err := m.loadNonce(from)
+	if err != nil {
+		return err
+	}
+	if ok && next == nonce+1 {
+		return m.storeNonce(from, nonce)
+	}
+
+	// sync again before handing out more, to pick up the gap
+	delete(m.synced, from)
+	return nil
+}
@@ -0,0 +1,204 @@
+package chain
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/memoio/memo-client/lib/address"
+	"github.com/memoio/memo-client/lib/chain/contracts"
+	"github.com/memoio/memo-client/wallet"
+	"golang.org/x/xerrors"
+)
+
+// TransactOpts signs the transactions of from with signer, which holds
+// the secp256k1 key of from. The fees are suggested now, the gas limit
+// is estimated per tx.
+func (c *Client) TransactOpts(ctx context.Context, signer wallet.Signer, from common.Address) (*bind.TransactOpts, error) {
+	fees, err := c.SuggestFees(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("suggest fees: %w", err)
+	}
+
+	opts := &bind.TransactOpts{
+		From: from,
+		Signer: func(a common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if a != from {
+				return nil, bind.ErrNotAuthorized
+			}
+			return SignTx(ctx, signer, from, tx, c.chainID)
+		},
+		Context: ctx,
+	}
+	fees.SetFees(opts)
+
+	return opts, nil
+}
+
+// UnsignedOpts builds txs of from without signing or sending them, for
+// signing offline.
+func (c *Client) UnsignedOpts(ctx context.Context, from common.Address) (*bind.TransactOpts, error) {
+	fees, err := c.SuggestFees(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("suggest fees: %w", err)
+	}
+
+	opts := &bind.TransactOpts{
+		From: from,
+		Signer: func(a common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		},
+		Context: ctx,
+		NoSend:  true,
+	}
+	fees.SetFees(opts)
+
+	return opts, nil
+}
+
+// SignTx signs tx for chainID with the key of from held by signer; it
+// needs no connection to the chain.
+func SignTx(ctx context.Context, signer wallet.Signer, from common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	addr, err := address.NewAddress(from.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	txSigner := types.LatestSignerForChainID(chainID)
+	hash := txSigner.Hash(tx)
+	sig, err := signer.WalletSign(ctx, addr, hash[:])
+	if err != nil {
+		return nil, xerrors.Errorf("sign tx: %w", err)
+	}
+	return tx.WithSignature(txSigner, sig)
+}
+
+// Token binds the MEMO token of the profile.
+func (c *Client) Token() (*contracts.ERC20, error) {
+	return contracts.NewERC20(c.profile.Token, gasBackend{c})
+}
+
+// Store binds the storage deposit contract of the profile.
+func (c *Client) Store() (*contracts.Store, error) {
+	return contracts.NewStore(c.profile.Store, gasBackend{c})
+}
+
+// Transfer sends amount MEMO to to.
+func (c *Client) Transfer(opts *bind.TransactOpts, to common.Address, amount *big.Int) (*types.Transaction, error) {
+	token, err := c.Token()
+	if err != nil {
+		return nil, err
+	}
+	return token.Transfer(opts, to, amount)
+}
+
+// Approve allows spender to take amount MEMO.
+func (c *Client) Approve(opts *bind.TransactOpts, spender common.Address, amount *big.Int) (*types.Transaction, error) {
+	token, err := c.Token()
+	if err != nil {
+		return nil, err
+	}
+	return token.Approve(opts, spender, amount)
+}
+
+// StoreDeposit deposits amount for the storage of to.
+func (c *Client) StoreDeposit(opts *bind.TransactOpts, to common.Address, amount *big.Int, memo string) (*types.Transaction, error) {
+	store, err := c.Store()
+	if err != nil {
+		return nil, err
+	}
+	return store.StoreDeposit(opts, to, amount, memo)
+}
+
+// Allowance returns how much MEMO spender may take from owner.
+func (c *Client) Allowance(ctx context.Context, owner, spender common.Address) (*big.Int, error) {
+	token, err := c.Token()
+	if err != nil {
+		return nil, err
+	}
+	return token.Allowance(&bind.CallOpts{Context: ctx}, owner, spender)
+}
+
+// BalanceOf returns the MEMO balance of owner.
+func (c *Client) BalanceOf(ctx context.Context, owner common.Address) (*big.Int, error) {
+	token, err := c.Token()
+	if err != nil {
+		return nil, err
+	}
+	return token.BalanceOf(&bind.CallOpts{Context: ctx}, owner)
+}
+
+// Deposited sums all MEMO owner ever paid into the store contract, read
+// from the token's transfer logs. It is a lifetime total, not what is
+// left of it; the store contract has no view of either.
+func (c *Client) Deposited(ctx context.Context, owner common.Address) (*big.Int, error) {
+	start, err := c.storeBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := c.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	it, err := token.FilterTransfer(&bind.FilterOpts{Start: start, Context: ctx}, []common.Address{owner}, []common.Address{c.profile.Store})
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	sum := new(big.Int)
+	for it.Next() {
+		sum.Add(sum, it.Event.Value)
+	}
+	return sum, it.Error()
+}
+
+// storeBlock returns the block the store contract was deployed at, from
+// the profile or else found by bisecting on its code.
+func (c *Client) storeBlock(ctx context.Context) (uint64, error) {
+	if c.profile.StoreBlock != 0 {
+		return c.profile.StoreBlock, nil
+	}
+
+	head, err := c.BlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	hasCode := func(n uint64) (bool, error) {
+		code, err := c.CodeAt(ctx, c.profile.Store, new(big.Int).SetUint64(n))
+		if err != nil {
+			return false, xerrors.Errorf("find store contract deploy block, set storeBlock in %s: %w", ProfileFile, err)
+		}
+		return len(code) > 0, nil
+	}
+
+	ok, err := hasCode(head)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, xerrors.Errorf("no store contract at %s", c.profile.Store)
+	}
+
+	lo, hi := uint64(0), head
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		ok, err := hasCode(mid)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	c.profile.StoreBlock = lo
+	return lo, nil
+}
@@ -0,0 +1,561 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/memoio/memo-client/lib/types/store"
+	"golang.org/x/xerrors"
+)
+
+// state key prefix of the txs sent and not yet confirmed
+const pendingTxPrefix = "tx/"
+
+const (
+	defaultConfirmations   = 1
+	defaultPollInterval    = time.Second
+	defaultMaxPollInterval = 15 * time.Second
+	defaultUnknownPolls    = 5
+)
+
+var (
+	ErrTxReplaced = xerrors.New("tx was replaced or dropped, its nonce is used")
+	ErrTxUnknown  = xerrors.New("tx is unknown to the node")
+	ErrTxMined    = xerrors.New("tx is already mined")
+)
+
+// PendingTx is a sent tx kept in the repo state until it is confirmed.
+type PendingTx struct {
+	Hash  common.Hash    `json:"hash"`
+	From  common.Address `json:"from"`
+	Nonce uint64         `json:"nonce"`
+	Raw   hexutil.Bytes  `json:"raw"`
+	// Replaces is the tx this one speeds up or cancels
+	Replaces common.Hash `json:"replaces,omitempty"`
+	Sent     time.Time   `json:"sent"`
+}
+
+// Tx decodes the signed tx.
+func (p *PendingTx) Tx() (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	err := tx.UnmarshalBinary(p.Raw)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// TxManager sends txs, records them in the repo state and follows them
+// until they have enough confirmations.
+type TxManager struct {
+	lk     sync.Mutex
+	c      *Client
+	ds     store.KVStore
+	synced map[common.Address]bool
+
+	Confirmations   uint64
+	PollInterval    time.Duration
+	MaxPollInterval time.Duration
+	// UnknownPolls bounds how long Wait polls for a tx the node does not
+	// know; a tracked one is sent again on each of these polls
+	UnknownPolls int
+	// ReserveTTL is how long a nonce handed out to a built tx stays
+	// reserved; an unsent tx's nonce is a gap once it passes
+	ReserveTTL time.Duration
+}
+
+func NewTxManager(c *Client, ds store.KVStore) *TxManager {
+	return &TxManager{
+		c:               c,
+		ds:              ds,
+		synced:          make(map[common.Address]bool),
+		Confirmations:   defaultConfirmations,
+		PollInterval:    defaultPollInterval,
+		MaxPollInterval: defaultMaxPollInterval,
+		UnknownPolls:    defaultUnknownPolls,
+		ReserveTTL:      defaultReserveTTL,
+	}
+}
+
+// Client returns the chain client m sends with.
+func (m *TxManager) Client() *Client {
+	return m.c
+}
+
+func pendingTxKey(hash common.Hash) []byte {
+	return []byte(pendingTxPrefix + hash.Hex())
+}
+
+// Send broadcasts tx and tracks it.
+func (m *TxManager) Send(ctx context.Context, tx *types.Transaction) error {
+	err := m.Track(tx, common.Hash{})
+	if err != nil {
+		return err
+	}
+
+	err = m.c.SendTransaction(ctx, tx)
+	if err != nil {
+		_ = m.ds.Delete(pendingTxKey(tx.Hash()))
+		from, serr := types.Sender(types.LatestSignerForChainID(m.c.chainID), tx)
+		if serr == nil {
+			m.ReleaseNonce(from, tx.Nonce())
+		}
+		return err
+	}
+	return nil
+}
+
+// Transact builds a tx with build from opts at the next nonce of the
+// account and sends it; build must not send, see bind.TransactOpts.NoSend.
+func (m *TxManager) Transact(ctx context.Context, opts *bind.TransactOpts, build func(*bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	err := m.SetNonce(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := build(opts)
+	if err != nil {
+		m.ReleaseNonce(opts.From, opts.Nonce.Uint64())
+		return nil, err
+	}
+
+	err = m.Send(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// Track records a signed tx, which replaces the tx replaces if it is set.
+func (m *TxManager) Track(tx *types.Transaction, replaces common.Hash) error {
+	from, err := types.Sender(types.LatestSignerForChainID(m.c.chainID), tx)
+	if err != nil {
+		return err
+	}
+
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	buf, err := json.Marshal(&PendingTx{
+		Hash:     tx.Hash(),
+		From:     from,
+		Nonce:    tx.Nonce(),
+		Raw:      raw,
+		Replaces: replaces,
+		Sent:     time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = m.ds.Put(pendingTxKey(tx.Hash()), buf)
+	if err != nil {
+		return err
+	}
+
+	// a tx built for offline signing is tracked from now on
+	return m.ds.Delete(reservedKey(from, tx.Nonce()))
+}
+
+// Get returns the record of a tracked tx.
+func (m *TxManager) Get(hash common.Hash) (*PendingTx, error) {
+	buf, err := m.ds.Get(pendingTxKey(hash))
+	if err != nil {
+		return nil, err
+	}
+
+	p := new(PendingTx)
+	err = json.Unmarshal(buf, p)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Pending lists the tracked txs by account and nonce.
+func (m *TxManager) Pending() ([]*PendingTx, error) {
+	var out []*PendingTx
+	var perr error
+	m.ds.Iter([]byte(pendingTxPrefix), func(k, v []byte) error {
+		p := new(PendingTx)
+		perr = json.Unmarshal(v, p)
+		if perr != nil {
+			perr = xerrors.Errorf("decode %s: %w", k, perr)
+			return perr
+		}
+		out = append(out, p)
+		return nil
+	})
+	if perr != nil {
+		return nil, perr
+	}
+
+	return out, nil
+}
+
+// forget drops tx and all others of its account and nonce, once one of
+// them is final.
+func (m *TxManager) forget(p *PendingTx) error {
+	all, err := m.Pending()
+	if err != nil {
+		return err
+	}
+
+	for _, o := range all {
+		if o.From == p.From && o.Nonce == p.Nonce {
+			err = m.ds.Delete(pendingTxKey(o.Hash))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TxStatus is what the chain knows of a tx.
+type TxStatus struct {
+	Hash    common.Hash
+	Pending bool
+	Receipt *types.Receipt
+	// Confirmations counts the blocks since the receipt's, it included
+	Confirmations uint64
+	// Reason is the revert reason of a failed tx
+	Reason string
+}
+
+func (s *TxStatus) String() string {
+	switch {
+	case s.Receipt == nil && s.Pending:
+		return "pending"
+	case s.Receipt == nil:
+		return "unknown"
+	case s.Receipt.Status == types.ReceiptStatusSuccessful:
+		return "mined"
+	default:
+		return "failed"
+	}
+}
+
+// Status looks up hash on chain; ErrTxUnknown is returned when the node
+// has neither the tx nor its receipt.
+func (m *TxManager) Status(ctx context.Context, hash common.Hash) (*TxStatus, error) {
+	s := &TxStatus{Hash: hash}
+
+	r, err := m.c.TransactionReceipt(ctx, hash)
+	switch {
+	case err == nil:
+		s.Receipt = r
+	case errors.Is(err, ethereum.NotFound):
+		_, pending, err := m.c.TransactionByHash(ctx, hash)
+		if errors.Is(err, ethereum.NotFound) {
+			return s, ErrTxUnknown
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.Pending = pending
+		return s, nil
+	default:
+		return nil, err
+	}
+
+	head, err := m.c.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if mined := r.BlockNumber.Uint64(); head >= mined {
+		s.Confirmations = head - mined + 1
+	}
+
+	if r.Status != types.ReceiptStatusSuccessful {
+		tx, _, err := m.c.TransactionByHash(ctx, hash)
+		if err == nil {
+			s.Reason, _ = m.c.RevertReason(ctx, tx, r.BlockNumber)
+		}
+	}
+
+	return s, nil
+}
+
+// Wait polls with backoff until hash has the configured confirmations.
+// A failed tx returns its receipt and an error with the revert reason. A
+// tx the node does not know is sent again when tracked, and after
+// UnknownPolls polls in a row ErrTxUnknown is returned; a tracked one
+// stays tracked.
+func (m *TxManager) Wait(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	p, err := m.Get(hash)
+	if err != nil && err != store.ErrNotFound {
+		return nil, err
+	}
+
+	interval := m.PollInterval
+	unknown := 0
+	for {
+		s, err := m.Status(ctx, hash)
+		switch {
+		case err == nil && s.Receipt != nil && s.Confirmations >= m.Confirmations:
+			if p != nil {
+				err = m.forget(p)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if s.Receipt.Status != types.ReceiptStatusSuccessful {
+				return s.Receipt, xerrors.Errorf("tx %s failed: %s", hash, s.Reason)
+			}
+			return s.Receipt, nil
+		case err == ErrTxUnknown && p != nil:
+			// once the nonce is used by another tx this one never lands
+			nonce, err := m.c.NonceAt(ctx, p.From, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			if nonce > p.Nonce {
+				_ = m.forget(p)
+				return nil, ErrTxReplaced
+			}
+
+			unknown++
+			if unknown >= m.UnknownPolls {
+				return nil, ErrTxUnknown
+			}
+
+			// the node dropped the tx or never got it
+			m.rebroadcast(ctx, p)
+		case err == ErrTxUnknown:
+			// a tx just sent elsewhere may take a moment to reach the node
+			unknown++
+			if unknown >= m.UnknownPolls {
+				return nil, ErrTxUnknown
+			}
+		case err == nil:
+			unknown = 0
+		case err != nil:
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		interval *= 2
+		if interval > m.MaxPollInterval {
+			interval = m.MaxPollInterval
+		}
+	}
+}
+
+// rebroadcast sends the tracked tx p again; errors are left to the next
+// poll, a tx the node already has or whose nonce is used shows there.
+func (m *TxManager) rebroadcast(ctx context.Context, p *PendingTx) {
+	tx, err := p.Tx()
+	if err != nil {
+		return
+	}
+	_ = m.c.SendTransaction(ctx, tx)
+}
+
+// bump raises a fee by an eighth, above the 10% nodes ask of replacements.
+func bump(v *big.Int) *big.Int {
+	inc := new(big.Int).Div(v, big.NewInt(8))
+	if inc.Sign() == 0 {
+		inc.SetInt64(1)
+	}
+	return inc.Add(inc, v)
+}
+
+// replaceFee returns a fee of a replacement: old bumped as far as nodes
+// require, raised to suggested when that is higher, and within max. A
+// bump alone above max is an error, nodes refuse anything less.
+func replaceFee(old, suggested *big.Int, max uint64, what string) (*big.Int, error) {
+	fee := bump(old)
+	if max != 0 && fee.Cmp(new(big.Int).SetUint64(max)) > 0 {
+		return nil, xerrors.Errorf("replacing needs a %s of %s wei, above the cap %d", what, fee, max)
+	}
+
+	if suggested != nil {
+		fee = maxBig(fee, suggested)
+	}
+	return capAt(fee, max), nil
+}
+
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+// Replace re-sends the tracked tx hash with the same nonce and higher
+// fees, signed by opts. A cancel sends nothing to the account itself
+// instead of the original call.
+func (m *TxManager) Replace(ctx context.Context, opts *bind.TransactOpts, hash common.Hash, cancel bool) (*types.Transaction, error) {
+	p, err := m.Get(hash)
+	if err == store.ErrNotFound {
+		return nil, xerrors.Errorf("tx %s is not tracked", hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := m.Status(ctx, hash)
+	if err != nil && err != ErrTxUnknown {
+		return nil, err
+	}
+	if s != nil && s.Receipt != nil {
+		return nil, ErrTxMined
+	}
+
+	old, err := p.Tx()
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.From != p.From {
+		return nil, xerrors.Errorf("tx %s is from %s, not %s", hash, p.From, opts.From)
+	}
+
+	to, value, data, gas := old.To(), old.Value(), old.Data(), old.Gas()
+	if cancel {
+		to, value, data, gas = &p.From, new(big.Int), nil, params.TxGas
+	}
+
+	fees, err := m.c.SuggestFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := m.c.profile.Gas
+
+	var inner types.TxData
+	if old.Type() == types.DynamicFeeTxType {
+		var sugTip, sugFeeCap *big.Int
+		if fees.Dynamic() {
+			sugTip, sugFeeCap = fees.GasTipCap, fees.GasFeeCap
+		}
+
+		tip, err := replaceFee(old.GasTipCap(), sugTip, cfg.MaxGasTip, "tip")
+		if err != nil {
+			return nil, err
+		}
+
+		feeCap, err := replaceFee(old.GasFeeCap(), sugFeeCap, cfg.MaxGasPrice, "fee cap")
+		if err != nil {
+			return nil, err
+		}
+
+		if tip.Cmp(feeCap) > 0 {
+			if cfg.MaxGasPrice != 0 && tip.Cmp(new(big.Int).SetUint64(cfg.MaxGasPrice)) > 0 {
+				return nil, xerrors.Errorf("replacing needs a tip of %s wei, above the fee cap limit %d", tip, cfg.MaxGasPrice)
+			}
+			feeCap = tip
+		}
+
+		inner = &types.DynamicFeeTx{
+			ChainID:   m.c.chainID,
+			Nonce:     p.Nonce,
+			GasTipCap: tip,
+			GasFeeCap: feeCap,
+			Gas:       gas,
+			To:        to,
+			Value:     value,
+			Data:      data,
+		}
+	} else {
+		var sugPrice *big.Int
+		if !fees.Dynamic() {
+			sugPrice = fees.GasPrice
+		}
+
+		price, err := replaceFee(old.GasPrice(), sugPrice, cfg.MaxGasPrice, "gas price")
+		if err != nil {
+			return nil, err
+		}
+
+		inner = &types.LegacyTx{
+			Nonce:    p.Nonce,
+			GasPrice: price,
+			Gas:      gas,
+			To:       to,
+			Value:    value,
+			Data:     data,
+		}
+	}
+
+	tx, err := opts.Signer(p.From, types.NewTx(inner))
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.Track(tx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.c.SendTransaction(ctx, tx)
+	if err != nil {
+		_ = m.ds.Delete(pendingTxKey(tx.Hash()))
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// RevertReason replays tx on the state before block and returns the
+// reason it reverted with.
+func (c *Client) RevertReason(ctx context.Context, tx *types.Transaction, block *big.Int) (string, error) {
+	from, err := types.Sender(types.LatestSignerForChainID(c.chainID), tx)
+	if err != nil {
+		return "", err
+	}
+
+	if block != nil && block.Sign() > 0 {
+		block = new(big.Int).Sub(block, big.NewInt(1))
+	}
+
+	_, err = c.CallContract(ctx, ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}, block)
+	if err == nil {
+		return "", nil
+	}
+
+	var de rpc.DataError
+	if errors.As(err, &de) {
+		if s, ok := de.ErrorData().(string); ok {
+			data, derr := hexutil.Decode(s)
+			if derr == nil {
+				reason, uerr := abi.UnpackRevert(data)
+				if uerr == nil {
+					return reason, nil
+				}
+			}
+		}
+	}
+
+	return strings.TrimPrefix(err.Error(), "execution reverted: "), nil
+}
@@ -0,0 +1,255 @@
+package chain
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/memoio/memo-client/lib/backend/kv"
+)
+
+// fakeEth is a node that never learns of any tx sent to it.
+type fakeEth struct {
+	lk    sync.Mutex
+	nonce uint64
+	sent  int
+
+	// fee suggestions, no base fee is a chain before London
+	baseFee *big.Int
+	tip     int64
+	price   int64
+}
+
+func (f *fakeEth) GetBlockByNumber(number string, full bool) (*types.Header, error) {
+	return &types.Header{
+		Number:     big.NewInt(1),
+		Difficulty: big.NewInt(1),
+		BaseFee:    f.baseFee,
+	}, nil
+}
+
+func (f *fakeEth) MaxPriorityFeePerGas() (*hexutil.Big, error) {
+	return (*hexutil.Big)(big.NewInt(f.tip)), nil
+}
+
+func (f *fakeEth) GasPrice() (*hexutil.Big, error) {
+	return (*hexutil.Big)(big.NewInt(f.price)), nil
+}
+
+func (f *fakeEth) GetTransactionReceipt(hash common.Hash) (*types.Receipt, error) {
+	return nil, nil
+}
+
+func (f *fakeEth) GetTransactionByHash(hash common.Hash) (*types.Transaction, error) {
+	return nil, nil
+}
+
+func (f *fakeEth) GetTransactionCount(addr common.Address, block string) (hexutil.Uint64, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	return hexutil.Uint64(f.nonce), nil
+}
+
+func (f *fakeEth) SendRawTransaction(raw hexutil.Bytes) (common.Hash, error) {
+	f.lk.Lock()
+	defer f.lk.Unlock()
+	f.sent++
+
+	tx := new(types.Transaction)
+	err := tx.UnmarshalBinary(raw)
+	return tx.Hash(), err
+}
+
+func newFakeManager(t *testing.T, f *fakeEth) *TxManager {
+	srv := rpc.NewServer()
+	err := srv.RegisterName("eth", f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(srv.Stop)
+
+	ds, err := kv.NewKVStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Client{
+		Client:  ethclient.NewClient(rpc.DialInProc(srv)),
+		chainID: big.NewInt(1),
+	}
+
+	m := NewTxManager(c, ds)
+	m.PollInterval = time.Millisecond
+	m.MaxPollInterval = time.Millisecond
+	m.UnknownPolls = 3
+	return m
+}
+
+func trackedTx(t *testing.T, m *TxManager, nonce uint64) *types.Transaction {
+	sk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := types.SignNewTx(sk, types.LatestSignerForChainID(m.c.chainID), &types.LegacyTx{
+		Nonce:    nonce,
+		To:       &common.Address{},
+		Gas:      21000,
+		GasPrice: big.NewInt(1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = m.Track(tx, common.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestWaitTrackedUnknown(t *testing.T) {
+	f := new(fakeEth)
+	m := newFakeManager(t, f)
+	tx := trackedTx(t, m, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := m.Wait(ctx, tx.Hash())
+	if err != ErrTxUnknown {
+		t.Fatalf("want ErrTxUnknown, got %v", err)
+	}
+
+	// sent again on every unknown poll but the one giving up
+	if f.sent != m.UnknownPolls-1 {
+		t.Fatalf("rebroadcast %d times, want %d", f.sent, m.UnknownPolls-1)
+	}
+
+	_, err = m.Get(tx.Hash())
+	if err != nil {
+		t.Fatalf("tx no longer tracked: %s", err)
+	}
+}
+
+func TestWaitTrackedReplaced(t *testing.T) {
+	f := &fakeEth{nonce: 1}
+	m := newFakeManager(t, f)
+	tx := trackedTx(t, m, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := m.Wait(ctx, tx.Hash())
+	if err != ErrTxReplaced {
+		t.Fatalf("want ErrTxReplaced, got %v", err)
+	}
+
+	_, err = m.Get(tx.Hash())
+	if err == nil {
+		t.Fatal("replaced tx still tracked")
+	}
+}
+
+func TestWaitUntrackedUnknown(t *testing.T) {
+	f := new(fakeEth)
+	m := newFakeManager(t, f)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := m.Wait(ctx, common.HexToHash("0x01"))
+	if err != ErrTxUnknown {
+		t.Fatalf("want ErrTxUnknown, got %v", err)
+	}
+	if f.sent != 0 {
+		t.Fatalf("untracked tx sent %d times", f.sent)
+	}
+}
+
+func TestReplaceFeeCaps(t *testing.T) {
+	legacy := func(price int64) types.TxData {
+		return &types.LegacyTx{To: &common.Address{}, Gas: 21000, GasPrice: big.NewInt(price)}
+	}
+	dynamic := func(tip, feeCap int64) types.TxData {
+		return &types.DynamicFeeTx{ChainID: big.NewInt(1), To: &common.Address{}, Gas: 21000, GasTipCap: big.NewInt(tip), GasFeeCap: big.NewInt(feeCap)}
+	}
+
+	cases := []struct {
+		name string
+		eth  *fakeEth
+		gas  GasConfig
+		old  types.TxData
+
+		price, tip, feeCap int64
+		fail               bool
+	}{
+		{name: "legacy bump", eth: &fakeEth{price: 50}, old: legacy(100), price: 112},
+		{name: "legacy suggested", eth: &fakeEth{price: 200}, old: legacy(100), price: 200},
+		{name: "legacy suggested capped", eth: &fakeEth{price: 200}, gas: GasConfig{MaxGasPrice: 150}, old: legacy(100), price: 150},
+		{name: "legacy bump above cap", eth: &fakeEth{price: 50}, gas: GasConfig{MaxGasPrice: 110}, old: legacy(100), fail: true},
+		{name: "dynamic bump", eth: &fakeEth{baseFee: big.NewInt(40), tip: 1}, old: dynamic(10, 100), tip: 11, feeCap: 112},
+		{name: "dynamic suggested capped", eth: &fakeEth{baseFee: big.NewInt(95), tip: 10}, gas: GasConfig{MaxGasPrice: 120}, old: dynamic(10, 100), tip: 11, feeCap: 120},
+		{name: "tip bump above cap", eth: &fakeEth{baseFee: big.NewInt(40), tip: 1}, gas: GasConfig{MaxGasTip: 10}, old: dynamic(10, 100), fail: true},
+		{name: "fee cap bump above cap", eth: &fakeEth{baseFee: big.NewInt(40), tip: 1}, gas: GasConfig{MaxGasPrice: 105}, old: dynamic(10, 100), fail: true},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			m := newFakeManager(t, c.eth)
+			m.c.profile.Gas = c.gas
+
+			sk, err := crypto.GenerateKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			old, err := types.SignNewTx(sk, types.LatestSignerForChainID(m.c.chainID), c.old)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = m.Track(old, common.Hash{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			opts, err := bind.NewKeyedTransactorWithChainID(sk, m.c.chainID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tx, err := m.Replace(context.Background(), opts, old.Hash(), false)
+			if c.fail {
+				if err == nil {
+					t.Fatalf("replaced with price %s tip %s fee cap %s, want an error", tx.GasPrice(), tx.GasTipCap(), tx.GasFeeCap())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if c.price != 0 {
+				if tx.GasPrice().Int64() != c.price {
+					t.Fatalf("gas price %s, want %d", tx.GasPrice(), c.price)
+				}
+				return
+			}
+
+			if tx.GasTipCap().Int64() != c.tip || tx.GasFeeCap().Int64() != c.feeCap {
+				t.Fatalf("tip %s fee cap %s, want %d %d", tx.GasTipCap(), tx.GasFeeCap(), c.tip, c.feeCap)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package chain
+
+import (
+	"math/big"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Decimals of MEMO, the smallest unit is called automemo.
+const Decimals = 18
+
+// ParseAmount parses a decimal MEMO amount such as 1.5 into automemo.
+func ParseAmount(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, xerrors.New("empty amount")
+	}
+
+	ip, fp := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		ip, fp = s[:i], s[i+1:]
+	}
+
+	if len(fp) > Decimals {
+		return nil, xerrors.Errorf("amount %s has more than %d decimals", s, Decimals)
+	}
+
+	v, ok := new(big.Int).SetString(ip+fp+strings.Repeat("0", Decimals-len(fp)), 10)
+	if !ok || v.Sign() < 0 {
+		return nil, xerrors.Errorf("invalid amount %s", s)
+	}
+	return v, nil
+}
+
+// FormatAmount prints automemo as decimal MEMO.
+func FormatAmount(v *big.Int) string {
+	if v == nil {
+		return "0"
+	}
+
+	neg := v.Sign() < 0
+	s := new(big.Int).Abs(v).String()
+	if len(s) <= Decimals {
+		s = strings.Repeat("0", Decimals-len(s)+1) + s
+	}
+
+	ip, fp := s[:len(s)-Decimals], strings.TrimRight(s[len(s)-Decimals:], "0")
+	if fp != "" {
+		ip += "." + fp
+	}
+	if neg {
+		ip = "-" + ip
+	}
+	return ip
+}
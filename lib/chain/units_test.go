@@ -0,0 +1,82 @@
+package chain
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string // automemo, empty when in is rejected
+	}{
+		{"1", "1000000000000000000"},
+		{"0", "0"},
+		{"1.5", "1500000000000000000"},
+		{" 2.25 ", "2250000000000000000"},
+		{"0.000000000000000001", "1"},
+		{"123456789.123456789123456789", "123456789123456789123456789"},
+		{"0.0000000000000000001", ""},
+		{"-1", ""},
+		{"-0.5", ""},
+		{"1e18", ""},
+		{"1.2.3", ""},
+		{"0x10", ""},
+		{"", ""},
+		{" ", ""},
+	}
+
+	for _, c := range cases {
+		v, err := ParseAmount(c.in)
+		if c.want == "" {
+			if err == nil {
+				t.Fatalf("%q: accepted as %s", c.in, v)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%q: %s", c.in, err)
+		}
+		if v.String() != c.want {
+			t.Fatalf("%q: got %s, want %s", c.in, v, c.want)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"0", "0"},
+		{"1", "0.000000000000000001"},
+		{"1000000000000000000", "1"},
+		{"1500000000000000000", "1.5"},
+		{"123456789123456789123456789", "123456789.123456789123456789"},
+		{"-2500000000000000000", "-2.5"},
+	}
+
+	for _, c := range cases {
+		v, _ := new(big.Int).SetString(c.in, 10)
+		got := FormatAmount(v)
+		if got != c.want {
+			t.Fatalf("%s: got %s, want %s", c.in, got, c.want)
+		}
+
+		// formatted amounts parse back to the same value
+		if v.Sign() >= 0 {
+			back, err := ParseAmount(got)
+			if err != nil {
+				t.Fatalf("%s: %s", got, err)
+			}
+			if back.Cmp(v) != 0 {
+				t.Fatalf("%s: parsed back as %s", got, back)
+			}
+		}
+	}
+
+	if FormatAmount(nil) != "0" {
+		t.Fatal("nil is not 0")
+	}
+}
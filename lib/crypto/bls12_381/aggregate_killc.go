@@ -0,0 +1,208 @@
+//go:build !herumi
+// +build !herumi
+
+package bls
+
+import (
+	"bytes"
+	"crypto/rand"
+
+	kbls "github.com/kilic/bls12-381"
+	"golang.org/x/xerrors"
+)
+
+var (
+	errEmptyBatch    = xerrors.New("empty bls batch")
+	errBatchMismatch = xerrors.New("bls batch length mismatch")
+	errDuplicateMsg  = xerrors.New("duplicate message in bls aggregate")
+)
+
+// batchScalarSize is the size of the random weights used by BatchVerify;
+// 128 bits keeps the forgery probability at 2^-128.
+const batchScalarSize = 16
+
+func parsePublicKey(g *kbls.G1, publicKey []byte) (*kbls.PointG1, error) {
+	if len(publicKey) != PublicKeySize {
+		return nil, errPublicKeySize
+	}
+
+	if bytes.Equal(zeroPublicKey, publicKey) {
+		return nil, errZeroPublicKey
+	}
+
+	pk, err := g.FromCompressed(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if g.IsZero(pk) {
+		return nil, errInfinitePublicKey
+	}
+
+	if !g.InCorrectSubgroup(pk) {
+		return nil, errInvalidPublicKey
+	}
+
+	return pk, nil
+}
+
+func parseSignature(g *kbls.G2, signature []byte) (*kbls.PointG2, error) {
+	if len(signature) != SignatureSize {
+		return nil, errSignatureSize
+	}
+
+	if bytes.Equal(zeroSignature, signature) {
+		return nil, errZeroSignature
+	}
+
+	sig, err := g.FromCompressed(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	if g.IsZero(sig) {
+		return nil, errInfiniteSignature
+	}
+
+	if !g.InCorrectSubgroup(sig) {
+		return nil, errInvalidSignature
+	}
+
+	return sig, nil
+}
+
+// AggregateVerify checks an aggregated signature over msgs[i] signed by
+// publicKeys[i], using one multi-pairing. The msgs must be distinct, same
+// message signatures go through FastAggregateVerify.
+func AggregateVerify(publicKeys, msgs [][]byte, signature []byte) error {
+	if len(publicKeys) == 0 {
+		return errEmptyBatch
+	}
+
+	if len(publicKeys) != len(msgs) {
+		return errBatchMismatch
+	}
+
+	seen := make(map[string]struct{}, len(msgs))
+	for _, msg := range msgs {
+		if _, ok := seen[string(msg)]; ok {
+			return errDuplicateMsg
+		}
+		seen[string(msg)] = struct{}{}
+	}
+
+	e := kbls.NewEngine()
+	sig, err := parseSignature(e.G2, signature)
+	if err != nil {
+		return err
+	}
+
+	for i := range publicKeys {
+		pk, err := parsePublicKey(e.G1, publicKeys[i])
+		if err != nil {
+			return err
+		}
+
+		M, err := e.G2.HashToCurve(msgs[i], dst)
+		if err != nil {
+			return err
+		}
+
+		e.AddPair(pk, M)
+	}
+
+	e.AddPairInv(e.G1.One(), sig)
+	if e.Check() {
+		return nil
+	}
+
+	return errInvalidSignature
+}
+
+// FastAggregateVerify checks an aggregated signature of publicKeys over the
+// same msg. The keys must come with a verified proof of possession.
+func FastAggregateVerify(publicKeys [][]byte, msg, signature []byte) error {
+	if len(publicKeys) == 0 {
+		return errEmptyBatch
+	}
+
+	g := kbls.NewG1()
+	aggregated := g.Zero()
+	for _, publicKey := range publicKeys {
+		pk, err := parsePublicKey(g, publicKey)
+		if err != nil {
+			return err
+		}
+
+		g.Add(aggregated, aggregated, pk)
+	}
+
+	return Verify(g.ToCompressed(aggregated), msg, signature)
+}
+
+// BatchVerify checks many independent (publicKey, msg, signature) triples at
+// once. Each triple is weighted by a random scalar so that invalid
+// signatures cannot cancel out; it fails if any one signature is invalid.
+func BatchVerify(publicKeys, msgs, signatures [][]byte) error {
+	if len(publicKeys) == 0 {
+		return errEmptyBatch
+	}
+
+	if len(publicKeys) != len(msgs) || len(publicKeys) != len(signatures) {
+		return errBatchMismatch
+	}
+
+	e := kbls.NewEngine()
+	aggregated := e.G2.Zero()
+	buf := make([]byte, batchScalarSize)
+	for i := range publicKeys {
+		pk, err := parsePublicKey(e.G1, publicKeys[i])
+		if err != nil {
+			return err
+		}
+
+		sig, err := parseSignature(e.G2, signatures[i])
+		if err != nil {
+			return err
+		}
+
+		M, err := e.G2.HashToCurve(msgs[i], dst)
+		if err != nil {
+			return err
+		}
+
+		r, err := randScalar(buf)
+		if err != nil {
+			return err
+		}
+
+		// e(r*pk, H(m)) and r*sig
+		rpk := e.G1.New()
+		e.G1.MulScalar(rpk, pk, r)
+		e.AddPair(rpk, M)
+
+		e.G2.MulScalar(sig, sig, r)
+		e.G2.Add(aggregated, aggregated, sig)
+	}
+
+	e.AddPairInv(e.G1.One(), aggregated)
+	if e.Check() {
+		return nil
+	}
+
+	return errInvalidSignature
+}
+
+func randScalar(buf []byte) (*kbls.Fr, error) {
+	for {
+		_, err := rand.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		r := new(kbls.Fr).FromBytes(buf)
+		if !r.IsZero() {
+			return r, nil
+		}
+	}
+}
@@ -0,0 +1,184 @@
+//go:build !herumi
+// +build !herumi
+
+package bls
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+)
+
+type triple struct {
+	pk, msg, sig []byte
+}
+
+func testTriples(tb testing.TB, n int, sameMsg bool) []triple {
+	tb.Helper()
+
+	out := make([]triple, n)
+	for i := range out {
+		sk, err := GenerateKey()
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		pk, err := PublicKey(sk)
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		m := fmt.Sprintf("msg %d", i)
+		if sameMsg {
+			m = "msg"
+		}
+		msg := sha256.Sum256([]byte(m))
+
+		sig, err := Sign(sk, msg[:])
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		out[i] = triple{pk: pk, msg: msg[:], sig: sig}
+	}
+	return out
+}
+
+func split(ts []triple) (pks, msgs, sigs [][]byte) {
+	for _, t := range ts {
+		pks = append(pks, t.pk)
+		msgs = append(msgs, t.msg)
+		sigs = append(sigs, t.sig)
+	}
+	return pks, msgs, sigs
+}
+
+func aggregate(t *testing.T, sigs [][]byte) []byte {
+	t.Helper()
+	sig, err := AggregateSignature(sigs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func TestAggregateVerify(t *testing.T) {
+	pks, msgs, sigs := split(testTriples(t, 4, false))
+	sig := aggregate(t, sigs)
+
+	err := AggregateVerify(pks, msgs, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the signature of one key over the message of another
+	swapped := aggregate(t, append([][]byte{sigs[1], sigs[0]}, sigs[2:]...))
+	err = AggregateVerify(append([][]byte{pks[1], pks[0]}, pks[2:]...), msgs, swapped)
+	if err == nil {
+		t.Fatal("swapped signature verified")
+	}
+
+	err = AggregateVerify(pks, msgs[:3], sig)
+	if err != errBatchMismatch {
+		t.Fatalf("length mismatch: %v", err)
+	}
+
+	err = AggregateVerify(nil, nil, sig)
+	if err != errEmptyBatch {
+		t.Fatalf("empty batch: %v", err)
+	}
+
+	dup := append([][]byte{msgs[0]}, msgs[:3]...)
+	err = AggregateVerify(pks, dup, sig)
+	if err != errDuplicateMsg {
+		t.Fatalf("duplicate message: %v", err)
+	}
+}
+
+func TestFastAggregateVerify(t *testing.T) {
+	pks, msgs, sigs := split(testTriples(t, 4, true))
+	sig := aggregate(t, sigs)
+
+	err := FastAggregateVerify(pks, msgs[0], sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other := sha256.Sum256([]byte("other"))
+	err = FastAggregateVerify(pks, other[:], sig)
+	if err == nil {
+		t.Fatal("signature verified for another message")
+	}
+
+	err = FastAggregateVerify(pks[:3], msgs[0], sig)
+	if err == nil {
+		t.Fatal("signature verified without one key")
+	}
+
+	err = FastAggregateVerify(nil, msgs[0], sig)
+	if err != errEmptyBatch {
+		t.Fatalf("empty batch: %v", err)
+	}
+}
+
+func TestBatchVerify(t *testing.T) {
+	pks, msgs, sigs := split(testTriples(t, 8, false))
+
+	err := BatchVerify(pks, msgs, sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	swapped := append([][]byte{sigs[1], sigs[0]}, sigs[2:]...)
+	err = BatchVerify(pks, msgs, swapped)
+	if err == nil {
+		t.Fatal("swapped signatures verified")
+	}
+
+	err = BatchVerify(pks, msgs, sigs[:7])
+	if err != errBatchMismatch {
+		t.Fatalf("length mismatch: %v", err)
+	}
+
+	err = BatchVerify(nil, nil, nil)
+	if err != errEmptyBatch {
+		t.Fatalf("empty batch: %v", err)
+	}
+
+	// triples are independent, one message may be signed by several keys
+	dpks, dmsgs, dsigs := split(testTriples(t, 3, true))
+	err = BatchVerify(dpks, dmsgs, dsigs)
+	if err != nil {
+		t.Fatalf("same message batch: %s", err)
+	}
+}
+
+const benchBatch = 64
+
+func BenchmarkBatchVerify(b *testing.B) {
+	pks, msgs, sigs := split(testTriples(b, benchBatch, false))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		err := BatchVerify(pks, msgs, sigs)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkNaiveVerify checks the same batch as BenchmarkBatchVerify with
+// one Verify per signature.
+func BenchmarkNaiveVerify(b *testing.B) {
+	pks, msgs, sigs := split(testTriples(b, benchBatch, false))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := range pks {
+			err := Verify(pks[j], msgs[j], sigs[j])
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package bls
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/xerrors"
+)
+
+// EIP-2333 key derivation, so keys can be reproduced by other tooling.
+
+// SigningKeyPath is the EIP-2334 path of the first signing key.
+const SigningKeyPath = "m/12381/3600/0/0/0"
+
+const (
+	minSeedSize    = 32
+	lamportChunks  = 255
+	hkdfModROutLen = 48
+)
+
+var (
+	errSeedSize = xerrors.New("bls seed is shorter than 32 bytes")
+	errBadPath  = xerrors.New("invalid bls derivation path")
+)
+
+// curveOrder is r, the order of the bls12-381 groups.
+var curveOrder, _ = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+
+var keygenSalt = []byte("BLS-SIG-KEYGEN-SALT-")
+
+// DeriveMasterKey returns the master secret key of seed.
+func DeriveMasterKey(seed []byte) ([]byte, error) {
+	if len(seed) < minSeedSize {
+		return nil, errSeedSize
+	}
+
+	return hkdfModR(seed)
+}
+
+// DeriveChildKey returns the child secret key of parent at index.
+func DeriveChildKey(parent []byte, index uint32) ([]byte, error) {
+	if len(parent) != SecretKeySize {
+		return nil, errSecretKeySize
+	}
+
+	return hkdfModR(parentToLamportPK(parent, index))
+}
+
+// DeriveKey derives the secret key of seed at path, like "m/12381/3600/0/0/0";
+// "m" alone is the master key.
+func DeriveKey(seed []byte, path string) ([]byte, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) == 0 || parts[0] != "m" {
+		return nil, errBadPath
+	}
+
+	sk, err := DeriveMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range parts[1:] {
+		if p == "" {
+			return nil, xerrors.Errorf("%w: empty segment in %q", errBadPath, path)
+		}
+
+		index, err := strconv.ParseUint(p, 10, 32)
+		if err != nil {
+			return nil, xerrors.Errorf("%w: %s", errBadPath, path)
+		}
+
+		sk, err = DeriveChildKey(sk, uint32(index))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sk, nil
+}
+
+func hkdfModR(ikm []byte) ([]byte, error) {
+	// IKM || I2OSP(0, 1)
+	in := make([]byte, len(ikm)+1)
+	copy(in, ikm)
+
+	// key_info is empty, so info is just I2OSP(L, 2)
+	info := []byte{0, hkdfModROutLen}
+
+	salt := keygenSalt
+	sk := new(big.Int)
+	for sk.Sign() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+
+		okm := make([]byte, hkdfModROutLen)
+		_, err := io.ReadFull(hkdf.New(sha256.New, in, salt, info), okm)
+		if err != nil {
+			return nil, err
+		}
+
+		sk.SetBytes(okm)
+		sk.Mod(sk, curveOrder)
+	}
+
+	return sk.FillBytes(make([]byte, SecretKeySize)), nil
+}
+
+func parentToLamportPK(parent []byte, index uint32) []byte {
+	salt := make([]byte, 4)
+	binary.BigEndian.PutUint32(salt, index)
+
+	notIKM := make([]byte, len(parent))
+	for i, b := range parent {
+		notIKM[i] = ^b
+	}
+
+	d := sha256.New()
+	for _, ikm := range [][]byte{parent, notIKM} {
+		lamport := ikmToLamportSK(ikm, salt)
+		for i := 0; i < lamportChunks; i++ {
+			chunk := sha256.Sum256(lamport[i*sha256.Size : (i+1)*sha256.Size])
+			d.Write(chunk[:])
+		}
+	}
+
+	return d.Sum(nil)
+}
+
+func ikmToLamportSK(ikm, salt []byte) []byte {
+	okm := make([]byte, sha256.Size*lamportChunks)
+	// reading up to 255 blocks from hkdf cannot fail
+	_, _ = io.ReadFull(hkdf.New(sha256.New, ikm, salt, nil), okm)
+	return okm
+}
@@ -0,0 +1,111 @@
+package bls
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strconv"
+	"testing"
+)
+
+// test cases of EIP-2333, the keys are decimal
+var eip2333Vectors = []struct {
+	seed   string
+	master string
+	index  uint32
+	child  string
+}{
+	{
+		seed:   "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		master: "6083874454709270928345386274498605044986640685124978867557563392430687146096",
+		index:  0,
+		child:  "20397789859736650942317412262472558107875392172444076792671091975210932703118",
+	},
+	{
+		seed:   "3141592653589793238462643383279502884197169399375105820974944592",
+		master: "29757020647961307431480504535336562678282505419141012933316116377660817309383",
+		index:  3141592653,
+		child:  "25457201688850691947727629385191704516744796114925897962676248250929345014287",
+	},
+	{
+		seed:   "0099ff991111002299dd7744ee3355bbdd8844115566cc55663355668888cc00",
+		master: "27580842291869792442942448775674722299803720648445448686099262467207037398656",
+		index:  4294967295,
+		child:  "29358610794459428860402234341874281240803786294062035874021252734817515685787",
+	},
+	{
+		seed:   "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+		master: "19022158461524446591288038168518313374041767046816487870552872741050760015818",
+		index:  42,
+		child:  "31372231650479070279774297061823572166496564838472787488249775572789064611981",
+	},
+}
+
+func decimalKey(t *testing.T, s string) []byte {
+	t.Helper()
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		t.Fatalf("bad key %s", s)
+	}
+	return v.FillBytes(make([]byte, SecretKeySize))
+}
+
+func TestEIP2333Vectors(t *testing.T) {
+	for i, v := range eip2333Vectors {
+		seed, err := hex.DecodeString(v.seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		master, err := DeriveMasterKey(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Equals(master, decimalKey(t, v.master)) {
+			t.Fatalf("case %d: master key %x", i, master)
+		}
+
+		child, err := DeriveChildKey(master, v.index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Equals(child, decimalKey(t, v.child)) {
+			t.Fatalf("case %d: child key %x", i, child)
+		}
+
+		byPath, err := DeriveKey(seed, "m/"+strconv.FormatUint(uint64(v.index), 10))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Equals(byPath, child) {
+			t.Fatalf("case %d: key by path differs", i)
+		}
+	}
+}
+
+func TestDeriveKeyPath(t *testing.T) {
+	seed, err := hex.DecodeString(eip2333Vectors[0].seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	master, err := DeriveKey(seed, "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equals(master, decimalKey(t, eip2333Vectors[0].master)) {
+		t.Fatal("m is not the master key")
+	}
+
+	for _, path := range []string{"", "m/", "m//0", "/0", "x/0", "m/-1", "m/4294967296", "m/0/"} {
+		_, err := DeriveKey(seed, path)
+		if !errors.Is(err, errBadPath) {
+			t.Fatalf("path %q: %v", path, err)
+		}
+	}
+
+	_, err = DeriveKey(seed[:31], "m")
+	if err != errSeedSize {
+		t.Fatalf("short seed: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+//go:build !herumi
+// +build !herumi
+
+package bls
+
+import (
+	kbls "github.com/kilic/bls12-381"
+)
+
+// PopProve returns the proof of possession of privateKey: a signature over
+// its own public key under the POP domain.
+func PopProve(privateKey []byte) ([]byte, error) {
+	publicKey, err := PublicKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sk := new(kbls.Fr).FromBytes(privateKey)
+
+	g := kbls.NewG2()
+	M, err := g.HashToCurve(publicKey, popDst)
+	if err != nil {
+		return nil, err
+	}
+	proof := g.New()
+	g.MulScalar(proof, M, sk)
+
+	return g.ToCompressed(proof), nil
+}
+
+// PopVerify checks the proof of possession of publicKey.
+func PopVerify(publicKey, proof []byte) error {
+	e := kbls.NewEngine()
+	pk, err := parsePublicKey(e.G1, publicKey)
+	if err != nil {
+		return err
+	}
+
+	sig, err := parseSignature(e.G2, proof)
+	if err != nil {
+		return errInvalidPop
+	}
+
+	M, err := e.G2.HashToCurve(publicKey, popDst)
+	if err != nil {
+		return err
+	}
+
+	e.AddPair(pk, M)
+	e.AddPairInv(e.G1.One(), sig)
+	if e.Check() {
+		return nil
+	}
+
+	return errInvalidPop
+}
+
+// AggregatePublicKeyWithPop aggregates publicKeys after checking that each
+// one comes with a valid proof, which rules out rogue key attacks.
+func AggregatePublicKeyWithPop(publicKeys, proofs [][]byte) ([]byte, error) {
+	if len(publicKeys) == 0 {
+		return nil, errEmptyBatch
+	}
+
+	if len(publicKeys) != len(proofs) {
+		return nil, errBatchMismatch
+	}
+
+	for i := range publicKeys {
+		err := PopVerify(publicKeys[i], proofs[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return AggregatePublicKey(publicKeys...)
+}
@@ -0,0 +1,90 @@
+//go:build !herumi
+// +build !herumi
+
+package bls
+
+import (
+	"testing"
+)
+
+func testKey(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+
+	sk, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pk, err := PublicKey(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sk, pk
+}
+
+func TestPop(t *testing.T) {
+	sk, pk := testKey(t)
+	_, otherPk := testKey(t)
+
+	proof, err := PopProve(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = PopVerify(pk, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = PopVerify(otherPk, proof)
+	if err != errInvalidPop {
+		t.Fatalf("proof checked against another key: %v", err)
+	}
+
+	// a signature over the public key is not a proof, the domains differ
+	sig, err := Sign(sk, pk[:32])
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = PopVerify(pk, sig)
+	if err == nil {
+		t.Fatal("signature accepted as proof")
+	}
+}
+
+func TestAggregatePublicKeyWithPop(t *testing.T) {
+	sk1, pk1 := testKey(t)
+	sk2, pk2 := testKey(t)
+
+	proof1, err := PopProve(sk1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof2, err := PopProve(sk2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agg, err := AggregatePublicKeyWithPop([][]byte{pk1, pk2}, [][]byte{proof1, proof2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := AggregatePublicKey(pk1, pk2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equals(agg, want) {
+		t.Fatal("aggregated key differs")
+	}
+
+	_, err = AggregatePublicKeyWithPop([][]byte{pk1, pk2}, [][]byte{proof2, proof1})
+	if err != errInvalidPop {
+		t.Fatalf("swapped proofs: %v", err)
+	}
+
+	_, err = AggregatePublicKeyWithPop([][]byte{pk1, pk2}, [][]byte{proof1})
+	if err != errBatchMismatch {
+		t.Fatalf("length mismatch: %v", err)
+	}
+}
@@ -24,6 +24,7 @@ var (
 	errInvalidSecretKey  = xerrors.New("invalid bls secret key")
 	errPublicKeySize     = xerrors.New("invalid bls public key size")
 	errSignatureSize     = xerrors.New("invalid bls signature size")
+	errInvalidPop        = xerrors.New("invalid bls proof of possession")
 )
 
 var zeroSecretKey = make([]byte, SecretKeySize)
@@ -34,6 +35,9 @@ var infiniteSignature []byte
 
 var dst = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
 
+// popDst separates proofs of possession from ordinary signatures.
+var popDst = []byte("BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
 func initSign() {
 	var err error
 	infinitePublicKeyStr := "c00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"
@@ -159,9 +159,11 @@ func AggregatePublicKey(publicKeys ...[]byte) ([]byte, error) {
 	return g.ToCompressed(aggregated), nil
 }
 
-// GenerateKeyFromSeed generates a new key from the given reader.
+// GenerateKeyFromSeed generates a new key from the hash of seed.
+// Use DeriveKey for keys other tooling can reproduce.
 func GenerateKeyFromSeed(seed []byte) ([]byte, error) {
-	sk := new(kbls.Fr).FromBytes(blake3.New().Sum(seed))
+	h := blake3.Sum256(seed)
+	sk := new(kbls.Fr).FromBytes(h[:])
 
 	return sk.ToBytes(), nil
 }
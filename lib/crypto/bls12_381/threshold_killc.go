@@ -0,0 +1,200 @@
+//go:build !herumi
+// +build !herumi
+
+package bls
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	kbls "github.com/kilic/bls12-381"
+	"golang.org/x/xerrors"
+)
+
+var (
+	errThreshold      = xerrors.New("invalid threshold, need 1 <= k <= n")
+	errShareID        = xerrors.New("invalid bls share id")
+	errDuplicateShare = xerrors.New("duplicate bls share id")
+	errInvalidShare   = xerrors.New("bls share does not match verification vector")
+)
+
+// KeyShare is the secret key share held by one of the n operators. Its
+// signatures are partial; any k of them recombine into a signature of the
+// group key.
+type KeyShare struct {
+	ID        uint64
+	SecretKey []byte
+}
+
+// SplitKey splits privateKey into n shares so that any k recover signatures.
+// The verification vector holds the commitments to the polynomial
+// coefficients; its first entry is the public key of privateKey.
+func SplitKey(privateKey []byte, k, n int) ([]KeyShare, [][]byte, error) {
+	if k < 1 || k > n {
+		return nil, nil, errThreshold
+	}
+
+	_, err := PublicKey(privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// f(x) = sk + a1*x + ... + a(k-1)*x^(k-1)
+	coeffs := make([]*big.Int, k)
+	coeffs[0] = new(big.Int).SetBytes(privateKey)
+	for i := 1; i < k; i++ {
+		coeffs[i], err = rand.Int(rand.Reader, curveOrder)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	g := kbls.NewG1()
+	vv := make([][]byte, k)
+	for i, c := range coeffs {
+		p := g.New()
+		g.MulScalarBig(p, g.One(), c)
+		vv[i] = g.ToCompressed(p)
+	}
+
+	shares := make([]KeyShare, n)
+	for i := range shares {
+		id := uint64(i + 1)
+		shares[i] = KeyShare{
+			ID:        id,
+			SecretKey: toSecretKey(evalPoly(coeffs, id)),
+		}
+	}
+
+	return shares, vv, nil
+}
+
+func evalPoly(coeffs []*big.Int, id uint64) *big.Int {
+	x := new(big.Int).SetUint64(id)
+	res := new(big.Int)
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		res.Mul(res, x)
+		res.Add(res, coeffs[i])
+		res.Mod(res, curveOrder)
+	}
+	return res
+}
+
+func toSecretKey(v *big.Int) []byte {
+	out := make([]byte, SecretKeySize)
+	return v.FillBytes(out)
+}
+
+// SharePublicKey returns the public key of share id from the verification vector.
+func SharePublicKey(vv [][]byte, id uint64) ([]byte, error) {
+	if id == 0 {
+		return nil, errShareID
+	}
+
+	if len(vv) == 0 {
+		return nil, errEmptyBatch
+	}
+
+	g := kbls.NewG1()
+	x := new(big.Int).SetUint64(id)
+	res := g.Zero()
+	for i := len(vv) - 1; i >= 0; i-- {
+		c, err := g.FromCompressed(vv[i])
+		if err != nil {
+			return nil, err
+		}
+
+		g.MulScalarBig(res, res, x)
+		g.Add(res, res, c)
+	}
+
+	return g.ToCompressed(res), nil
+}
+
+// VerifyShare checks that a received share is consistent with the
+// published verification vector.
+func VerifyShare(share KeyShare, vv [][]byte) error {
+	want, err := SharePublicKey(vv, share.ID)
+	if err != nil {
+		return err
+	}
+
+	got, err := PublicKey(share.SecretKey)
+	if err != nil {
+		return err
+	}
+
+	if !Equals(want, got) {
+		return errInvalidShare
+	}
+	return nil
+}
+
+// PartialSign signs msg with one share.
+func PartialSign(share KeyShare, msg []byte) ([]byte, error) {
+	if share.ID == 0 {
+		return nil, errShareID
+	}
+	return Sign(share.SecretKey, msg)
+}
+
+// Recombine interpolates k partial signatures into the signature of the
+// group key, verifiable with Verify against vv[0].
+func Recombine(ids []uint64, signatures [][]byte) ([]byte, error) {
+	if len(ids) == 0 {
+		return nil, errEmptyBatch
+	}
+
+	if len(ids) != len(signatures) {
+		return nil, errBatchMismatch
+	}
+
+	seen := make(map[uint64]struct{}, len(ids))
+	for _, id := range ids {
+		if id == 0 {
+			return nil, errShareID
+		}
+		if _, ok := seen[id]; ok {
+			return nil, errDuplicateShare
+		}
+		seen[id] = struct{}{}
+	}
+
+	g := kbls.NewG2()
+	res := g.Zero()
+	for i, id := range ids {
+		sig, err := g.FromCompressed(signatures[i])
+		if err != nil {
+			return nil, err
+		}
+
+		g.MulScalarBig(sig, sig, lagrangeAtZero(ids, id))
+		g.Add(res, res, sig)
+	}
+
+	return g.ToCompressed(res), nil
+}
+
+// lagrangeAtZero returns prod_{j != i} x_j / (x_j - x_i) mod r.
+func lagrangeAtZero(ids []uint64, i uint64) *big.Int {
+	num := big.NewInt(1)
+	den := big.NewInt(1)
+	xi := new(big.Int).SetUint64(i)
+	for _, j := range ids {
+		if j == i {
+			continue
+		}
+
+		xj := new(big.Int).SetUint64(j)
+		num.Mul(num, xj)
+		num.Mod(num, curveOrder)
+
+		d := new(big.Int).Sub(xj, xi)
+		den.Mul(den, d)
+		den.Mod(den, curveOrder)
+	}
+
+	den.ModInverse(den, curveOrder)
+	num.Mul(num, den)
+	return num.Mod(num, curveOrder)
+}
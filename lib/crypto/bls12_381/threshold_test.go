@@ -0,0 +1,109 @@
+//go:build !herumi
+// +build !herumi
+
+package bls
+
+import (
+	"crypto/sha256"
+	"testing"
+)
+
+func partialSigs(t *testing.T, shares []KeyShare, msg []byte) ([]uint64, [][]byte) {
+	t.Helper()
+
+	var ids []uint64
+	var sigs [][]byte
+	for _, s := range shares {
+		sig, err := PartialSign(s, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, s.ID)
+		sigs = append(sigs, sig)
+	}
+	return ids, sigs
+}
+
+func TestThreshold(t *testing.T) {
+	const k, n = 3, 5
+
+	sk, pk := testKey(t)
+	msg := sha256.Sum256([]byte("upload"))
+
+	shares, vv, err := SplitKey(sk, k, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(shares) != n || len(vv) != k {
+		t.Fatalf("got %d shares and %d commitments", len(shares), len(vv))
+	}
+	if !Equals(vv[0], pk) {
+		t.Fatal("verification vector does not start with the public key")
+	}
+
+	for _, s := range shares {
+		err := VerifyShare(s, vv)
+		if err != nil {
+			t.Fatalf("share %d: %s", s.ID, err)
+		}
+	}
+
+	want, err := Sign(sk, msg[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// any k shares give the signature of the group key
+	for _, set := range [][]KeyShare{shares[:k], shares[n-k:], {shares[4], shares[0], shares[2]}} {
+		ids, sigs := partialSigs(t, set, msg[:])
+		sig, err := Recombine(ids, sigs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = Verify(pk, msg[:], sig)
+		if err != nil {
+			t.Fatalf("shares %v: %s", ids, err)
+		}
+		if !Equals(sig, want) {
+			t.Fatalf("shares %v: signature differs from the one of the key", ids)
+		}
+	}
+
+	ids, sigs := partialSigs(t, shares[:k-1], msg[:])
+	sig, err := Recombine(ids, sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Verify(pk, msg[:], sig)
+	if err == nil {
+		t.Fatal("k-1 shares gave a valid signature")
+	}
+
+	ids, sigs = partialSigs(t, []KeyShare{shares[0], shares[1], shares[1]}, msg[:])
+	_, err = Recombine(ids, sigs)
+	if err != errDuplicateShare {
+		t.Fatalf("duplicate share: %v", err)
+	}
+}
+
+func TestThresholdBadShare(t *testing.T) {
+	sk, _ := testKey(t)
+	other, _ := testKey(t)
+
+	shares, vv, err := SplitKey(sk, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = VerifyShare(KeyShare{ID: shares[0].ID, SecretKey: other}, vv)
+	if err != errInvalidShare {
+		t.Fatalf("foreign share: %v", err)
+	}
+
+	_, _, err = SplitKey(sk, 4, 3)
+	if err != errThreshold {
+		t.Fatalf("k > n: %v", err)
+	}
+}
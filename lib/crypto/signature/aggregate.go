@@ -0,0 +1,38 @@
+package signature
+
+import (
+	"github.com/memoio/memo-client/lib/crypto/signature/bls"
+)
+
+// only bls signatures aggregate; pubs are raw bls public keys
+
+func AggregateSignature(sigs ...[]byte) ([]byte, error) {
+	return bls.AggregateSignature(sigs...)
+}
+
+func AggregateVerify(pubs, msgs [][]byte, sig []byte) (bool, error) {
+	return bls.AggregateVerify(pubs, msgs, sig)
+}
+
+func FastAggregateVerify(pubs [][]byte, msg, sig []byte) (bool, error) {
+	return bls.FastAggregateVerify(pubs, msg, sig)
+}
+
+func BatchVerify(pubs, msgs, sigs [][]byte) (bool, error) {
+	return bls.BatchVerify(pubs, msgs, sigs)
+}
+
+func AggregatePublicKey(pubs, proofs [][]byte) ([]byte, error) {
+	return bls.AggregatePublicKey(pubs, proofs)
+}
+
+// PopVerify checks the proof of possession of a bls public key.
+func PopVerify(pub, proof []byte) (bool, error) {
+	pk := &bls.PublicKey{}
+	err := pk.Deserialize(pub)
+	if err != nil {
+		return false, err
+	}
+
+	return pk.PopVerify(proof)
+}
@@ -0,0 +1,62 @@
+package bls
+
+import (
+	bls "github.com/memoio/memo-client/lib/crypto/bls12_381"
+	"lukechampine.com/blake3"
+)
+
+// msgHash hashes data the same way Sign and Verify do.
+func msgHash(data []byte) []byte {
+	if len(data) != 32 {
+		msg := blake3.Sum256(data)
+		return msg[:]
+	}
+	return data
+}
+
+func hashAll(msgs [][]byte) [][]byte {
+	out := make([][]byte, len(msgs))
+	for i, msg := range msgs {
+		out[i] = msgHash(msg)
+	}
+	return out
+}
+
+// AggregateSignature combines signatures into one.
+func AggregateSignature(sigs ...[]byte) ([]byte, error) {
+	return bls.AggregateSignature(sigs...)
+}
+
+// AggregateVerify checks an aggregated signature over msgs[i] signed by pubs[i].
+func AggregateVerify(pubs, msgs [][]byte, sig []byte) (bool, error) {
+	err := bls.AggregateVerify(pubs, hashAll(msgs), sig)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FastAggregateVerify checks an aggregated signature of pubs over one msg.
+// pubs must have verified proofs of possession, see AggregatePublicKey.
+func FastAggregateVerify(pubs [][]byte, msg, sig []byte) (bool, error) {
+	err := bls.FastAggregateVerify(pubs, msgHash(msg), sig)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// BatchVerify checks many (pub, msg, sig) triples in one multi-pairing.
+func BatchVerify(pubs, msgs, sigs [][]byte) (bool, error) {
+	err := bls.BatchVerify(pubs, hashAll(msgs), sigs)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// AggregatePublicKey aggregates pubs, refusing any key whose proof of
+// possession does not verify.
+func AggregatePublicKey(pubs, proofs [][]byte) ([]byte, error) {
+	return bls.AggregatePublicKeyWithPop(pubs, proofs)
+}
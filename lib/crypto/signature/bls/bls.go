@@ -82,6 +82,16 @@ func (k *PrivateKey) Sign(data []byte) ([]byte, error) {
 	return sig, nil
 }
 
+// PopProve returns the proof of possession of the key
+func (k *PrivateKey) PopProve() ([]byte, error) {
+	sk, err := k.Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	return bls.PopProve(sk)
+}
+
 // GetPublic returns a public key
 func (k *PrivateKey) GetPublic() common.PubKey {
 	if k.PublicKey != nil {
@@ -150,6 +160,21 @@ func (k *PublicKey) Deserialize(data []byte) error {
 	return nil
 }
 
+// PopVerify checks the proof of possession of the key
+func (k *PublicKey) PopVerify(proof []byte) (bool, error) {
+	pubBytes, err := k.Raw()
+	if err != nil {
+		return false, err
+	}
+
+	err = bls.PopVerify(pubBytes, proof)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 func (k *PublicKey) Verify(data, sig []byte) (bool, error) {
 	pubBytes, err := k.Raw()
 	if err != nil {
@@ -172,3 +197,21 @@ func (k *PublicKey) Verify(data, sig []byte) (bool, error) {
 
 	return true, nil
 }
+
+// SigningKeyPath is the EIP-2334 path of the first signing key
+const SigningKeyPath = bls.SigningKeyPath
+
+// DeriveKey derives the key at path from seed as in EIP-2333
+func DeriveKey(seed []byte, path string) (common.PrivKey, error) {
+	sk, err := bls.DeriveKey(seed, path)
+	if err != nil {
+		return nil, err
+	}
+
+	priv := &PrivateKey{}
+	err = priv.Deserialize(sk)
+	if err != nil {
+		return nil, err
+	}
+	return priv, nil
+}
@@ -2,12 +2,15 @@ package signature
 
 import (
 	"bytes"
+	"fmt"
 
 	"github.com/memoio/memo-client/lib/crypto/signature/bls"
 	"github.com/memoio/memo-client/lib/crypto/signature/common"
+	"github.com/memoio/memo-client/lib/crypto/signature/ed25519"
 	"github.com/memoio/memo-client/lib/crypto/signature/secp256k1"
 	"github.com/memoio/memo-client/lib/types"
 	"github.com/memoio/memo-client/lib/utils"
+	"golang.org/x/crypto/sha3"
 	"golang.org/x/xerrors"
 	"lukechampine.com/blake3"
 )
@@ -18,6 +21,8 @@ func GenerateKey(typ types.KeyType) (common.PrivKey, error) {
 		return bls.GenerateKey()
 	case types.Secp256k1:
 		return secp256k1.GenerateKey()
+	case types.Ed25519:
+		return ed25519.GenerateKey()
 	default:
 		return nil, common.ErrBadKeyType
 	}
@@ -38,6 +43,12 @@ func ParsePrivateKey(privatekey []byte, typ types.KeyType) (common.PrivKey, erro
 		if err != nil {
 			return nil, err
 		}
+	case types.Ed25519:
+		privkey = &ed25519.PrivateKey{}
+		err := privkey.Deserialize(privatekey)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, xerrors.Errorf("%d is %w", typ, common.ErrBadKeyType)
 	}
@@ -50,6 +61,12 @@ func ParsePubByte(pubbyte []byte) (common.PubKey, error) {
 	var pubKey common.PubKey
 	plen := len(pubbyte)
 	switch plen {
+	case ed25519.PublicKeySize:
+		pubKey = &ed25519.PublicKey{}
+		err := pubKey.Deserialize(pubbyte)
+		if err != nil {
+			return nil, err
+		}
 	case 33, 65:
 		pubKey = &secp256k1.PublicKey{}
 		err := pubKey.Deserialize(pubbyte)
@@ -94,3 +111,11 @@ func Verify(pubBytes []byte, data, sig []byte) (bool, error) {
 		return pk.Verify(data, sig)
 	}
 }
+
+// PersonalHash returns the EIP-191 hash signed by eth personal_sign.
+func PersonalHash(msg []byte) []byte {
+	d := sha3.NewLegacyKeccak256()
+	fmt.Fprintf(d, "\x19Ethereum Signed Message:\n%d", len(msg))
+	d.Write(msg)
+	return d.Sum(nil)
+}
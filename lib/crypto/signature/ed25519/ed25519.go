@@ -0,0 +1,167 @@
+package ed25519
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/subtle"
+
+	"github.com/memoio/memo-client/lib/crypto/signature/common"
+	"github.com/memoio/memo-client/lib/types"
+)
+
+const (
+	SignatureSize = ed25519.SignatureSize
+	SecretKeySize = ed25519.SeedSize
+	PublicKeySize = ed25519.PublicKeySize
+)
+
+var _ common.PrivKey = (*PrivateKey)(nil)
+var _ common.PubKey = (*PublicKey)(nil)
+
+// PrivateKey keeps the 32 byte seed as its raw form, the expanded key is
+// derived from it.
+type PrivateKey struct {
+	*PublicKey
+	secretKey ed25519.PrivateKey
+}
+
+type PublicKey struct {
+	pubKey ed25519.PublicKey
+}
+
+// GenerateKey generates a new Ed25519 private and public key pair
+func GenerateKey() (common.PrivKey, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PrivateKey{
+		PublicKey: &PublicKey{pub},
+		secretKey: priv,
+	}, nil
+}
+
+// Equals compares two private keys
+func (k *PrivateKey) Equals(o common.Key) bool {
+	if o.Type() != types.Ed25519 {
+		return false
+	}
+
+	a, err := k.Raw()
+	if err != nil {
+		return false
+	}
+	b, err := o.Raw()
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// Type returns the private key type
+func (k *PrivateKey) Type() types.KeyType {
+	return types.Ed25519
+}
+
+// Raw returns the seed of the key
+func (k *PrivateKey) Raw() ([]byte, error) {
+	if len(k.secretKey) != ed25519.PrivateKeySize {
+		return nil, common.ErrBadPrivateKey
+	}
+	return k.secretKey.Seed(), nil
+}
+
+// Sign returns a signature of data; ed25519 hashes internally so data is
+// signed as is.
+func (k *PrivateKey) Sign(data []byte) ([]byte, error) {
+	if len(k.secretKey) != ed25519.PrivateKeySize {
+		return nil, common.ErrBadPrivateKey
+	}
+
+	return ed25519.Sign(k.secretKey, data), nil
+}
+
+// GetPublic returns a public key
+func (k *PrivateKey) GetPublic() common.PubKey {
+	if k.PublicKey != nil {
+		return k.PublicKey
+	}
+	if len(k.secretKey) != ed25519.PrivateKeySize {
+		return nil
+	}
+
+	k.PublicKey = &PublicKey{k.secretKey.Public().(ed25519.PublicKey)}
+	return k.PublicKey
+}
+
+// Deserialize sets the key from its seed
+func (k *PrivateKey) Deserialize(data []byte) error {
+	if len(data) != SecretKeySize {
+		return common.ErrBadPrivateKey
+	}
+
+	k.secretKey = ed25519.NewKeyFromSeed(data)
+	k.PublicKey = &PublicKey{k.secretKey.Public().(ed25519.PublicKey)}
+	return nil
+}
+
+// Equals compares two public keys
+func (k *PublicKey) Equals(o common.Key) bool {
+	if o.Type() != types.Ed25519 {
+		return false
+	}
+
+	a, err := k.Raw()
+	if err != nil {
+		return false
+	}
+	b, err := o.Raw()
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// Type returns the public key type
+func (k *PublicKey) Type() types.KeyType {
+	return types.Ed25519
+}
+
+// Raw returns the bytes of the key
+func (k *PublicKey) Raw() ([]byte, error) {
+	if len(k.pubKey) != PublicKeySize {
+		return nil, common.ErrBadPublickKey
+	}
+
+	return k.pubKey, nil
+}
+
+// CompressedByte returns the raw key, ed25519 keys are always compressed
+func (k *PublicKey) CompressedByte() ([]byte, error) {
+	return k.Raw()
+}
+
+// Deserialize sets the public key to a copy of data, so the caller may
+// reuse its buffer
+func (k *PublicKey) Deserialize(data []byte) error {
+	if len(data) != PublicKeySize {
+		return common.ErrBadPublickKey
+	}
+	k.pubKey = append(ed25519.PublicKey(nil), data...)
+	return nil
+}
+
+// Verify compares a signature against the input data
+func (k *PublicKey) Verify(data, sig []byte) (bool, error) {
+	pubBytes, err := k.Raw()
+	if err != nil {
+		return false, err
+	}
+
+	if len(sig) != SignatureSize {
+		return false, common.ErrBadSign
+	}
+
+	return ed25519.Verify(pubBytes, data, sig), nil
+}
@@ -0,0 +1,142 @@
+package ed25519
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// vector of RFC 8032, section 7.1, test 2
+const (
+	vecSeed = "4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb"
+	vecPub  = "3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c"
+	vecMsg  = "72"
+	vecSig  = "92a009a9f0d4cab8720e820b5f642540a2b27b5416503f8fb3762223ebdb69da085ac1e43e15996e458f3613d0f11d8c387b2eaeb4302aeeb00d291612bb0c00"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSignVector(t *testing.T) {
+	k := &PrivateKey{}
+	err := k.Deserialize(mustHex(t, vecSeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, err := k.GetPublic().Raw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(pub) != vecPub {
+		t.Fatalf("public key %x, want %s", pub, vecPub)
+	}
+
+	sig, err := k.Sign(mustHex(t, vecMsg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(sig) != vecSig {
+		t.Fatalf("signature %x, want %s", sig, vecSig)
+	}
+
+	ok, err := k.GetPublic().Verify(mustHex(t, vecMsg), sig)
+	if err != nil || !ok {
+		t.Fatalf("vector signature does not verify: %v", err)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	k, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte("memo")
+	sig, err := k.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := k.GetPublic().Verify(msg, sig)
+	if err != nil || !ok {
+		t.Fatalf("signature does not verify: %v", err)
+	}
+
+	ok, err = k.GetPublic().Verify([]byte("other"), sig)
+	if err != nil || ok {
+		t.Fatalf("signature verifies another message: %v", err)
+	}
+
+	sig[0] ^= 1
+	ok, err = k.GetPublic().Verify(msg, sig)
+	if err != nil || ok {
+		t.Fatalf("tampered signature verifies: %v", err)
+	}
+
+	_, err = k.GetPublic().Verify(msg, sig[1:])
+	if err == nil {
+		t.Fatal("short signature accepted")
+	}
+}
+
+func TestSerialize(t *testing.T) {
+	k, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seed, err := k.Raw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seed) != SecretKeySize {
+		t.Fatalf("seed of %d bytes", len(seed))
+	}
+
+	k2 := &PrivateKey{}
+	err = k2.Deserialize(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !k.Equals(k2) || !k.GetPublic().Equals(k2.GetPublic()) {
+		t.Fatal("key changed by its seed round trip")
+	}
+
+	pub, err := k.GetPublic().Raw()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := append([]byte(nil), pub...)
+	pk := &PublicKey{}
+	err = pk.Deserialize(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the key must not follow its caller's buffer
+	for i := range buf {
+		buf[i] = 0
+	}
+	got, err := pk.Raw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, pub) {
+		t.Fatal("public key changed with the deserialized buffer")
+	}
+
+	if (&PrivateKey{}).Deserialize(seed[1:]) == nil {
+		t.Fatal("short seed accepted")
+	}
+	if (&PublicKey{}).Deserialize(pub[1:]) == nil {
+		t.Fatal("short public key accepted")
+	}
+}
@@ -0,0 +1,98 @@
+//go:build !cgo
+// +build !cgo
+
+package secp256k1
+
+import (
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/xerrors"
+)
+
+var (
+	errInvalidMsgLen       = xerrors.New("invalid message length, need 32 bytes")
+	errInvalidSignatureLen = xerrors.New("invalid signature length")
+	errInvalidRecoveryID   = xerrors.New("invalid signature recovery id")
+	errInvalidKey          = xerrors.New("invalid private key")
+)
+
+// Sign signs the given message, which must be 32 bytes long.
+// The signature is in [R || S || V] format where V is 0 or 1.
+func Sign(sk, msg []byte) ([]byte, error) {
+	if len(msg) != 32 {
+		return nil, errInvalidMsgLen
+	}
+
+	if len(sk) != SecretKeySize {
+		return nil, errInvalidKey
+	}
+
+	var priv secp256k1.PrivateKey
+	overflow := priv.Key.SetByteSlice(sk)
+	if overflow || priv.Key.IsZero() {
+		return nil, errInvalidKey
+	}
+	defer priv.Zero()
+
+	// compact signature is [V+27 || R || S]
+	sig := ecdsa.SignCompact(&priv, msg, false)
+
+	v := sig[0] - 27
+	copy(sig, sig[1:])
+	sig[SignatureSize-1] = v
+	return sig, nil
+}
+
+// Verify checks the given signature and returns true if it is valid.
+func Verify(pk, msg, signature []byte) bool {
+	if len(signature) == SignatureSize {
+		signature = signature[:64]
+	}
+
+	if len(msg) != 32 || len(signature) != 64 {
+		return false
+	}
+
+	var r, s secp256k1.ModNScalar
+	if r.SetByteSlice(signature[:32]) || s.SetByteSlice(signature[32:]) {
+		return false
+	}
+
+	// reject malleable signatures, as libsecp256k1 does
+	if s.IsOverHalfOrder() {
+		return false
+	}
+
+	key, err := secp256k1.ParsePubKey(pk)
+	if err != nil {
+		return false
+	}
+
+	return ecdsa.NewSignature(&r, &s).Verify(msg, key)
+}
+
+// EcRecover recovers the public key from a message, signature pair.
+func EcRecover(msg, signature []byte) ([]byte, error) {
+	if len(msg) != 32 {
+		return nil, errInvalidMsgLen
+	}
+
+	if len(signature) != SignatureSize {
+		return nil, errInvalidSignatureLen
+	}
+
+	if signature[SignatureSize-1] >= 4 {
+		return nil, errInvalidRecoveryID
+	}
+
+	compact := make([]byte, SignatureSize)
+	compact[0] = signature[SignatureSize-1] + 27
+	copy(compact[1:], signature[:64])
+
+	pub, _, err := ecdsa.RecoverCompact(compact, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return pub.SerializeUncompressed(), nil
+}
@@ -0,0 +1,128 @@
+package secp256k1
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+// vectors are shared by the cgo and the pure Go builds, run the tests
+// with CGO_ENABLED=1 and CGO_ENABLED=0 to check both give the same output
+var vectors = []struct {
+	sk  string
+	msg string
+	sig string
+	pub string
+}{
+	{
+		sk:  "0000000000000000000000000000000000000000000000000000000000000001",
+		msg: "abc",
+		sig: "75601b1385909ea698e3fd6e26e5fa5105127bd2299d3ab0b9d9f93df5b8b99c28ae7cc8f969e6b6fb1feac477818a75a46e8c364e88dfdc9880e1a5175c4bd101",
+		pub: "0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+	},
+	{
+		sk:  "c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+		msg: "sample",
+		sig: "432310e32cb80eb6503a26ce83cc165c783b870845fb8aad6d970889fcd7a6c8530128b6b81c548874a6305d93ed071ca6e05074d85863d4056ce89b02bfab6900",
+		pub: "042c8c31fc9f990c6b55e3865a184a4ce50e09481f2eaeb3e60ec1cea13a6ae64564b95e4fdb6948c0386e189b006a29f686769b011704275e4459822dc3328085",
+	},
+	{
+		sk:  "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+		msg: "test",
+		sig: "65b79d53819915fe61f7f57d82134a73386e3f7fd0c791232f26fc1b942991e1093a2c182134be3c4f39ac1f06ada089fcfcdd5d50f116bcfdc9e5e76e22a02601",
+		pub: "0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798b7c52588d95c3b9aa25b0403f1eef75702e84bb7597aabe663b82f6f04ef2777",
+	},
+}
+
+var curveN, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSignVectors(t *testing.T) {
+	for _, v := range vectors {
+		sk := mustHex(t, v.sk)
+		msg := sha256.Sum256([]byte(v.msg))
+		want := mustHex(t, v.sig)
+		pub := mustHex(t, v.pub)
+
+		sig, err := Sign(sk, msg[:])
+		if err != nil {
+			t.Fatalf("sign %q: %s", v.msg, err)
+		}
+		if !bytes.Equal(sig, want) {
+			t.Fatalf("sign %q: got %x, want %x", v.msg, sig, want)
+		}
+
+		rec, err := EcRecover(msg[:], sig)
+		if err != nil {
+			t.Fatalf("recover %q: %s", v.msg, err)
+		}
+		if !bytes.Equal(rec, pub) {
+			t.Fatalf("recover %q: got %x, want %x", v.msg, rec, pub)
+		}
+
+		if !Verify(pub, msg[:], sig) {
+			t.Fatalf("verify %q failed", v.msg)
+		}
+
+		other := sha256.Sum256([]byte(v.msg + "x"))
+		if Verify(pub, other[:], sig) {
+			t.Fatalf("verify %q passed for another message", v.msg)
+		}
+	}
+}
+
+func TestVerifyRejectsHighS(t *testing.T) {
+	v := vectors[0]
+	msg := sha256.Sum256([]byte(v.msg))
+	sig := mustHex(t, v.sig)
+
+	// (r, n-s) is the same signature with the other s, valid for plain ecdsa
+	s := new(big.Int).SetBytes(sig[32:64])
+	s.Sub(curveN, s)
+	high := make([]byte, SignatureSize)
+	copy(high, sig[:32])
+	s.FillBytes(high[32:64])
+	high[64] = sig[64] ^ 1
+
+	if Verify(mustHex(t, v.pub), msg[:], high) {
+		t.Fatal("high s signature verified")
+	}
+}
+
+func TestRecoverRejectsRecoveryID(t *testing.T) {
+	v := vectors[0]
+	msg := sha256.Sum256([]byte(v.msg))
+
+	for _, id := range []byte{4, 27, 255} {
+		sig := mustHex(t, v.sig)
+		sig[64] = id
+		_, err := EcRecover(msg[:], sig)
+		if err == nil {
+			t.Fatalf("recovery id %d accepted", id)
+		}
+	}
+}
+
+func TestSignRejectsBadInput(t *testing.T) {
+	msg := sha256.Sum256([]byte("abc"))
+
+	_, err := Sign(make([]byte, SecretKeySize), msg[:])
+	if err == nil {
+		t.Fatal("zero key accepted")
+	}
+
+	_, err = Sign(mustHex(t, vectors[0].sk), msg[:31])
+	if err == nil {
+		t.Fatal("short message accepted")
+	}
+}
@@ -0,0 +1,101 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/memoio/memo-client/lib/pb"
+	miniogo "github.com/minio/minio-go/v7"
+	"golang.org/x/xerrors"
+)
+
+// The role records come from gateway extensions of the minio-go fork,
+// next to QueryPrice and GetBalanceInfo; like Approve they carry binary
+// data hex encoded, here protobuf records and signatures.
+
+// RoleInfoHash is what the keys of a role sign at registration.
+func RoleInfoHash(ri *pb.RoleInfo) ([]byte, error) {
+	buf, err := ri.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(buf), nil
+}
+
+// GetRoleInfo fetches the role record of roleID from the gateway.
+func GetRoleInfo(ctx context.Context, client *miniogo.Client, roleID uint64) (*pb.RoleInfo, error) {
+	res, err := client.GetRoleInfo(ctx, strconv.FormatUint(roleID, 10))
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := hex.DecodeString(res)
+	if err != nil {
+		return nil, xerrors.Errorf("decode role info: %w", err)
+	}
+
+	ri := new(pb.RoleInfo)
+	err = ri.Unmarshal(buf)
+	if err != nil {
+		return nil, xerrors.Errorf("decode role info: %w", err)
+	}
+	return ri, nil
+}
+
+// GetNetInfo fetches the network record of roleID from the gateway.
+func GetNetInfo(ctx context.Context, client *miniogo.Client, roleID uint64) (*pb.NetInfo, error) {
+	res, err := client.GetNetInfo(ctx, strconv.FormatUint(roleID, 10))
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := hex.DecodeString(res)
+	if err != nil {
+		return nil, xerrors.Errorf("decode net info: %w", err)
+	}
+
+	ni := new(pb.NetInfo)
+	err = ni.Unmarshal(buf)
+	if err != nil {
+		return nil, xerrors.Errorf("decode net info: %w", err)
+	}
+	return ni, nil
+}
+
+// RegisterRole submits ri with the signatures of its chain and bls keys
+// over RoleInfoHash, and returns the record with its assigned RoleID.
+func RegisterRole(ctx context.Context, client *miniogo.Client, ri *pb.RoleInfo, sig, blsSig []byte) (*pb.RoleInfo, error) {
+	buf, err := ri.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.RegisterRole(ctx, hex.EncodeToString(buf), hex.EncodeToString(sig), hex.EncodeToString(blsSig))
+	if err != nil {
+		return nil, err
+	}
+
+	nbuf, err := hex.DecodeString(res)
+	if err != nil {
+		return nil, xerrors.Errorf("decode role info: %w", err)
+	}
+
+	nri := new(pb.RoleInfo)
+	err = nri.Unmarshal(nbuf)
+	if err != nil {
+		return nil, xerrors.Errorf("decode role info: %w", err)
+	}
+
+	if nri.GetRoleID() == 0 {
+		return nil, xerrors.New("gateway assigned no role id")
+	}
+
+	if !bytes.Equal(nri.GetChainVerifyKey(), ri.GetChainVerifyKey()) || !bytes.Equal(nri.GetBlsVerifyKey(), ri.GetBlsVerifyKey()) {
+		return nil, xerrors.Errorf("gateway registered role %d with other keys", nri.GetRoleID())
+	}
+
+	return nri, nil
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/memoio/memo-client/lib/backend/keystore"
+	"github.com/memoio/memo-client/lib/backend/kv"
 	"github.com/memoio/memo-client/lib/types"
 	"github.com/memoio/memo-client/lib/types/store"
 	"github.com/mitchellh/go-homedir"
@@ -14,6 +15,8 @@ import (
 
 const (
 	keyStorePathPrefix = "keystore"
+	metaPathPrefix     = "meta"
+	statePathPrefix    = "state"
 )
 
 type FSRepo struct {
@@ -106,6 +109,27 @@ func (r *FSRepo) loadFromDisk() error {
 	if err != nil {
 		return xerrors.Errorf("failed to open keystore %w", err)
 	}
+
+	err = r.openDatastores()
+	if err != nil {
+		return xerrors.Errorf("failed to open datastores %w", err)
+	}
+	return nil
+}
+
+func (r *FSRepo) openDatastores() error {
+	ds, err := kv.NewKVStore(filepath.Join(r.path, metaPathPrefix))
+	if err != nil {
+		return err
+	}
+	r.metaDs = ds
+
+	ds, err = kv.NewKVStore(filepath.Join(r.path, statePathPrefix))
+	if err != nil {
+		return err
+	}
+	r.stateDs = ds
+
 	return nil
 }
 
@@ -127,6 +151,16 @@ func (r *FSRepo) Close() error {
 	if err != nil {
 		return xerrors.Errorf("failed to close key store %w", err)
 	}
+
+	err = r.metaDs.Close()
+	if err != nil {
+		return xerrors.Errorf("failed to close meta store %w", err)
+	}
+
+	err = r.stateDs.Close()
+	if err != nil {
+		return xerrors.Errorf("failed to close state store %w", err)
+	}
 	return nil
 }
 
@@ -134,6 +168,14 @@ func (r *FSRepo) KeyStore() types.KeyStore {
 	return r.keyDs
 }
 
+func (r *FSRepo) MetaStore() store.KVStore {
+	return r.metaDs
+}
+
+func (r *FSRepo) StateStore() store.KVStore {
+	return r.stateDs
+}
+
 func (r *FSRepo) Path() (string, error) {
 	return r.path, nil
 }
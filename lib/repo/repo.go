@@ -1,10 +1,18 @@
 package repo
 
-import "github.com/memoio/memo-client/lib/types"
+import (
+	"github.com/memoio/memo-client/lib/types"
+	"github.com/memoio/memo-client/lib/types/store"
+)
 
 type Repo interface {
 	KeyStore() types.KeyStore
 
+	// MetaStore holds client metadata, StateStore the state of ongoing
+	// work such as the registered role
+	MetaStore() store.KVStore
+	StateStore() store.KVStore
+
 	Path() (string, error)
 
 	Close() error
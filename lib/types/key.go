@@ -1,5 +1,7 @@
 package types
 
+import "golang.org/x/xerrors"
+
 type KeyType = byte
 
 const (
@@ -29,3 +31,31 @@ type KeyStore interface {
 
 	Close() error
 }
+
+var keyTypeNames = map[KeyType]string{
+	RSA:       "rsa",
+	Secp256k1: "secp256k1",
+	BLS:       "bls",
+	PDP:       "pdp",
+	Ed25519:   "ed25519",
+	Hmac:      "hmac",
+}
+
+// KeyTypeString returns the name of a key type, as used on the command line
+func KeyTypeString(typ KeyType) string {
+	name, ok := keyTypeNames[typ]
+	if !ok {
+		return "unknown"
+	}
+	return name
+}
+
+// ParseKeyType is the inverse of KeyTypeString
+func ParseKeyType(s string) (KeyType, error) {
+	for typ, name := range keyTypeNames {
+		if name == s {
+			return typ, nil
+		}
+	}
+	return 0, xerrors.Errorf("unknown key type %s", s)
+}
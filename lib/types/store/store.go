@@ -1,5 +1,7 @@
 package store
 
+import "golang.org/x/xerrors"
+
 type DiskStats struct {
 	Path  string `json:"path"`
 	Total uint64 `json:"all"`
@@ -28,8 +30,17 @@ type KVStore interface {
 	NewTxnStore(bool) (TxnStore, error)
 }
 
+// KeyLocker is implemented by stores that other processes may open at
+// the same time; LockKey holds key exclusively across all of them until
+// the returned unlock is called.
+type KeyLocker interface {
+	LockKey(key []byte) (unlock func(), err error)
+}
+
 type TxnStore interface {
 	Store
 	Commit() error
 	Discard()
 }
+
+var ErrNotFound = xerrors.New("not found")
@@ -6,19 +6,14 @@ import (
 
 	"github.com/memoio/memo-client/cmd"
 	"github.com/memoio/memo-client/lib"
+	"github.com/memoio/memo-client/lib/address"
 	"github.com/urfave/cli/v2"
 )
 
 func main() {
+	// gateway credentials come from the keystore unless set in env
 	ak := os.Getenv("ACCESS_KEY")
-	if ak == "" {
-		ak = "memo"
-	}
-
 	sk := os.Getenv("SECRET_KEY")
-	if sk == "" {
-		sk = "memoriae"
-	}
 
 	endpoint := os.Getenv("ENDPOINT")
 	if endpoint == "" {
@@ -29,6 +24,15 @@ func main() {
 	lib.SecretKey = sk
 	lib.EndPoint = endpoint
 
+	if network := os.Getenv("NETWORK"); network != "" {
+		n, err := address.ParseNetwork(network)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %s\n\n", err)
+			os.Exit(1)
+		}
+		address.CurrentNetwork = n
+	}
+
 	local := make([]*cli.Command, 0)
 	local = append(local, cmd.PutObjectCmd)
 	local = append(local, cmd.QueryCmd)
@@ -37,6 +41,14 @@ func main() {
 	local = append(local, cmd.GetObjectCmd)
 	local = append(local, cmd.ListObjectCmd)
 	local = append(local, cmd.WalletCmd)
+	local = append(local, cmd.AgentCmd)
+	local = append(local, cmd.SignCmd)
+	local = append(local, cmd.VerifyCmd)
+	local = append(local, cmd.CredsCmd)
+	local = append(local, cmd.AddressCmd)
+	local = append(local, cmd.RoleCmd)
+	local = append(local, cmd.RegisterCmd)
+	local = append(local, cmd.TxCmd)
 	// local = append(local, cmd.InitBucketCmd)
 
 	app := &cli.App{
@@ -0,0 +1,93 @@
+package wallet
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/memoio/memo-client/lib/types"
+	"golang.org/x/xerrors"
+)
+
+// gateway credentials are stored as Hmac keys named by their access key
+const credsPrefix = "hmac-"
+
+var ErrNoCreds = xerrors.New("no gateway credentials in keystore")
+
+func credsName(accessKey string) string {
+	return credsPrefix + accessKey
+}
+
+// CredsImport stores the secret key of an S3 access key pair.
+func (w *LocalWallet) CredsImport(ctx context.Context, accessKey string, secretKey []byte) error {
+	if accessKey == "" || strings.ContainsAny(accessKey, `/\`) {
+		return xerrors.Errorf("invalid access key %q", accessKey)
+	}
+
+	if len(secretKey) == 0 {
+		return xerrors.New("empty secret key")
+	}
+
+	have, err := w.CredsList(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, ak := range have {
+		if ak == accessKey {
+			return xerrors.Errorf("credentials of %s already exist", accessKey)
+		}
+	}
+
+	ki := types.KeyInfo{
+		Type:      types.Hmac,
+		SecretKey: secretKey,
+	}
+
+	return w.keystore.Put(credsName(accessKey), w.password, ki)
+}
+
+// CredsList returns the stored access keys; it needs no password.
+func (w *LocalWallet) CredsList(ctx context.Context) ([]string, error) {
+	names, err := w.keystore.List()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, credsPrefix) {
+			out = append(out, strings.TrimPrefix(name, credsPrefix))
+		}
+	}
+
+	sort.Strings(out)
+
+	return out, nil
+}
+
+// CredsExport returns the secret key of accessKey.
+func (w *LocalWallet) CredsExport(ctx context.Context, accessKey string) ([]byte, error) {
+	w.lw.Lock()
+	sk, ok := w.creds[accessKey]
+	w.lw.Unlock()
+	if ok {
+		return sk, nil
+	}
+
+	ki, err := w.keystore.Get(credsName(accessKey), w.password)
+	if err != nil {
+		return nil, err
+	}
+
+	if ki.Type != types.Hmac {
+		return nil, xerrors.Errorf("key of %s is not hmac", accessKey)
+	}
+
+	return ki.SecretKey, nil
+}
+
+// CredsDelete removes accessKey from the keystore.
+func (w *LocalWallet) CredsDelete(ctx context.Context, accessKey string) error {
+	return w.keystore.Delete(credsName(accessKey), w.password)
+}
@@ -16,11 +16,18 @@ import (
 	"golang.org/x/xerrors"
 )
 
+// Signer signs messages with keys held in a keystore; implemented by
+// LocalWallet and by the agent client.
+type Signer interface {
+	WalletSign(ctx context.Context, addr address.Address, msg []byte) ([]byte, error)
+}
+
 type LocalWallet struct {
 	lw       sync.Mutex
 	password string // used for decrypt; todo plaintext is not good
 	accounts map[address.Address]common.PrivKey
-	keystore types.KeyStore // store
+	creds    map[string][]byte // gateway secret keys decrypted by Unlock
+	keystore types.KeyStore    // store
 }
 
 func GetSk(ctx context.Context, repoDir, pw string, addr address.Address) (string, error) {
@@ -48,6 +55,7 @@ func New(pw string, ks types.KeyStore) *LocalWallet {
 		password: pw,
 		keystore: ks,
 		accounts: make(map[address.Address]common.PrivKey),
+		creds:    make(map[string][]byte),
 	}
 
 	return lw
@@ -55,7 +63,7 @@ func New(pw string, ks types.KeyStore) *LocalWallet {
 
 func (w *LocalWallet) WalletImport(ctx context.Context, ki *types.KeyInfo) (address.Address, error) {
 	switch ki.Type {
-	case types.Secp256k1, types.BLS:
+	case types.Secp256k1, types.BLS, types.Ed25519:
 		privkey, err := signature.ParsePrivateKey(ki.SecretKey, ki.Type)
 		if err != nil {
 			return address.Undef, err
@@ -73,7 +81,7 @@ func (w *LocalWallet) WalletImport(ctx context.Context, ki *types.KeyInfo) (addr
 			return address.Undef, err
 		}
 
-		err = w.keystore.Put(addr.String(), w.password, *ki)
+		err = w.keystore.Put(addr.LegacyString(), w.password, *ki)
 		if err != nil {
 			return address.Undef, err
 		}
@@ -90,7 +98,7 @@ func (w *LocalWallet) WalletImport(ctx context.Context, ki *types.KeyInfo) (addr
 			if err != nil {
 				return address.Undef, err
 			}
-			err = w.keystore.Put(eaddr.String(), w.password, *ki)
+			err = w.keystore.Put(eaddr.LegacyString(), w.password, *ki)
 			if err != nil {
 				return address.Undef, err
 			}
@@ -116,7 +124,7 @@ func (w *LocalWallet) WalletList(ctx context.Context) ([]address.Address, error)
 
 	for _, s := range as {
 		if strings.HasPrefix(s, address.AddrPrefix) {
-			addr, err := address.NewFromString(s)
+			addr, err := address.NewFromLegacyString(s)
 			if err != nil {
 				continue
 			}
@@ -150,7 +158,7 @@ func (w *LocalWallet) find(addr address.Address) (common.PrivKey, error) {
 		return pi, nil
 	}
 
-	ki, err := w.keystore.Get(addr.String(), w.password)
+	ki, err := w.keystore.Get(addr.LegacyString(), w.password)
 	if err != nil {
 		return nil, err
 	}
@@ -166,9 +174,62 @@ func (w *LocalWallet) find(addr address.Address) (common.PrivKey, error) {
 }
 
 func (w *LocalWallet) WalletExport(ctx context.Context, addr address.Address, pw string) (*types.KeyInfo, error) {
-	ki, err := w.keystore.Get(addr.String(), pw)
+	ki, err := w.keystore.Get(addr.LegacyString(), pw)
 	if err != nil {
 		return nil, err
 	}
 	return &ki, nil
 }
+
+// Unlock decrypts every key and gateway credential in the keystore into
+// memory and then forgets the password, so the wallet can only sign and
+// hand out credentials until it is locked again.
+func (w *LocalWallet) Unlock(ctx context.Context) error {
+	addrs, err := w.WalletList(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range addrs {
+		_, err := w.find(addr)
+		if err != nil {
+			return xerrors.Errorf("unlock %s: %w", addr, err)
+		}
+	}
+
+	aks, err := w.CredsList(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, ak := range aks {
+		sk, err := w.CredsExport(ctx, ak)
+		if err != nil {
+			return xerrors.Errorf("unlock creds %s: %w", ak, err)
+		}
+
+		w.lw.Lock()
+		w.creds[ak] = sk
+		w.lw.Unlock()
+	}
+
+	w.lw.Lock()
+	w.password = ""
+	w.lw.Unlock()
+
+	return nil
+}
+
+// Lock drops all decrypted keys held in memory.
+func (w *LocalWallet) Lock() {
+	w.lw.Lock()
+	defer w.lw.Unlock()
+
+	for addr := range w.accounts {
+		delete(w.accounts, addr)
+	}
+
+	for ak := range w.creds {
+		delete(w.creds, ak)
+	}
+}